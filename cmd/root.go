@@ -1,29 +1,90 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"go/token"
+	"maps"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/tools/go/packages"
 
 	"github.com/kokhanevych/gomockgen/internal/generator"
 	"github.com/kokhanevych/gomockgen/internal/importer"
 	"github.com/kokhanevych/gomockgen/internal/template"
 )
 
+// importerOptions represent a set of options to use when importing packages.
+type importerOptions struct {
+	fromType    bool
+	unexported  bool
+	allowErrors bool
+	include     []string
+	exclude     []string
+	tags        string
+	goos        string
+	goarch      string
+	dir         string
+	mod         string
+	verbose     bool
+}
+
+// buildFlags returns the build flags to use when loading packages.
+func (o importerOptions) buildFlags() []string {
+	var flags []string
+	if o.tags != "" {
+		flags = append(flags, "-tags="+o.tags)
+	}
+
+	if o.mod != "" {
+		flags = append(flags, "-mod="+o.mod)
+	}
+
+	return flags
+}
+
+// env returns the environment to use when loading packages, nil for the environment of the process.
+func (o importerOptions) env() []string {
+	if o.goos == "" && o.goarch == "" {
+		return nil
+	}
+
+	env := os.Environ()
+	if o.goos != "" {
+		env = append(env, "GOOS="+o.goos)
+	}
+
+	if o.goarch != "" {
+		env = append(env, "GOARCH="+o.goarch)
+	}
+
+	return env
+}
+
 var (
 	options          generator.Options
+	importOptions    importerOptions
 	templateFileName string
+	sourceFileName   string
 )
 
 var cmd = &cobra.Command{
-	Use:   "gomockgen <import-path> [<interface>...]",
+	Use:   "gomockgen <import-path>... [<interface>...]",
 	Short: "Mock generator for Go interfaces based on text/template",
-	Args:  cobra.MinimumNArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) (err error) {
-		importPath := args[0]
+	Args: func(cmd *cobra.Command, args []string) error {
+		if sourceFileName != "" {
+			return nil
+		}
 
-		i, err := newImporter(importPath, options.FileName, options.MockPackage)
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		i, err := newImporter(importOptions)
 		if err != nil {
 			return err
 		}
@@ -35,21 +96,99 @@ var cmd = &cobra.Command{
 
 		g := generator.New(i, t)
 
-		b, err := g.Generate(importPath, options, args[1:]...)
+		if sourceFileName != "" {
+			pkg, err := i.LoadFile(sourceFileName)
+			if err != nil {
+				return err
+			}
+
+			b, err := generate(g, i, pkg.PkgPath, options, args)
+			if err != nil {
+				return err
+			}
+
+			return write(options.FileName, b)
+		}
+
+		importPaths, interfaces := splitArgs(args)
+
+		pkgs, err := i.Load(importPaths...)
 		if err != nil {
 			return err
 		}
 
-		return write(options.FileName, b)
+		if !several(importPaths, options.FileName) && len(pkgs) == 1 {
+			b, err := generate(g, i, pkgs[0].PkgPath, options, interfaces)
+			if err != nil {
+				return err
+			}
+
+			return write(options.FileName, b)
+		}
+
+		if options.FileName == "" {
+			return errors.New("an output directory or pattern is required for several packages")
+		}
+
+		if len(interfaces) > 0 && len(pkgs) > 1 {
+			return errors.New("interfaces can only be listed for a single package, use --include and --exclude instead")
+		}
+
+		files := make(map[string][]byte)
+		for _, pkg := range pkgs {
+			o := options
+			o.FileName = outFile(options.FileName, pkg)
+			o.SkipEmpty = true
+
+			b, err := generate(g, i, pkg.PkgPath, o, interfaces)
+			if err != nil {
+				return err
+			}
+
+			if b == nil {
+				continue
+			}
+
+			if _, ok := files[o.FileName]; ok {
+				return fmt.Errorf("several packages are generated to %s", o.FileName)
+			}
+
+			files[o.FileName] = b
+		}
+
+		if err := i.CheckPatterns(); err != nil {
+			return err
+		}
+
+		for _, fileName := range slices.Sorted(maps.Keys(files)) {
+			if err := write(fileName, files[fileName]); err != nil {
+				return err
+			}
+		}
+
+		return nil
 	},
 }
 
 func init() {
-	cmd.Flags().StringToStringVarP(&options.MockNames, "names", "n", nil, "comma-separated interfaceName=mockName pairs of explicit mock names to use. Default mock names are interface names")
-	cmd.Flags().StringVarP(&options.FileName, "out", "o", "", "output file instead of stdout")
+	cmd.Flags().StringToStringVarP(&options.MockNames, "names", "n", nil, "comma-separated interfaceName=mockName pairs of explicit mock names to use, where interfaceName may be an instantiation such as Repo[int]. Default mock names are interface names")
+	cmd.Flags().StringVarP(&options.FileName, "out", "o", "", "output file instead of stdout. For several packages, output directory or file pattern where {dir} and {name} are the directory and the name of the package")
 	cmd.Flags().StringVarP(&options.MockPackage, "package", "p", "", "package of the generated code (default is the package of the interfaces)")
-	cmd.Flags().StringVarP(&templateFileName, "template", "t", "", "template file used to generate the mock (default is the testify template)")
+	cmd.Flags().StringVarP(&templateFileName, "template", "t", "", "template file used to generate the mock, or builtin:<name> for a built-in template: testify, gomock, moq or fake (default is the testify template)")
+	cmd.Flags().StringVar(&sourceFileName, "source", "", "Go source file to mock the interfaces of instead of import paths, type-checked on its own")
+	cmd.Flags().BoolVar(&options.NameResults, "name-results", false, "name unnamed and blank results r0, r1, ... for templates using named results")
 	cmd.Flags().StringToStringVarP(&options.Substitutions, "substitutions", "s", nil, "comma-separated key=value pairs of substitutions to make when expanding the template")
+	cmd.Flags().BoolVar(&importOptions.fromType, "from-type", false, "mock the listed struct types from their method sets and extract their interfaces")
+	cmd.Flags().BoolVar(&importOptions.unexported, "unexported", false, "mock unexported interfaces too, which requires the generated code to be in the package of the interfaces")
+	cmd.Flags().BoolVar(&importOptions.allowErrors, "allow-errors", false, "generate mocks despite package errors, skipping the interfaces with invalid types")
+	cmd.Flags().StringArrayVar(&importOptions.include, "include", nil, "regular expression of the names of the interfaces to mock in addition to the listed ones")
+	cmd.Flags().StringArrayVar(&importOptions.exclude, "exclude", nil, "regular expression of the names of the interfaces not to mock")
+	cmd.Flags().StringVar(&importOptions.tags, "tags", "", "comma-separated list of build tags to consider satisfied when loading packages")
+	cmd.Flags().StringVar(&importOptions.goos, "goos", "", "target operating system when loading packages (default is $GOOS)")
+	cmd.Flags().StringVar(&importOptions.goarch, "goarch", "", "target architecture when loading packages (default is $GOARCH)")
+	cmd.Flags().StringVar(&importOptions.dir, "dir", "", "directory in which to load packages (default is the current directory)")
+	cmd.Flags().StringVar(&importOptions.mod, "mod", "", "module download mode to use when loading packages: readonly, vendor or mod")
+	cmd.Flags().BoolVarP(&importOptions.verbose, "verbose", "v", false, "print the module, version and directory each interface is loaded from to stderr")
 }
 
 // Execute executes the root command.
@@ -57,21 +196,115 @@ func Execute() error {
 	return cmd.Execute()
 }
 
-func newImporter(importPath, fileName, mockPackage string) (i *importer.Importer, err error) {
-	b := &importer.QualifierBuilder{}
+func newImporter(o importerOptions) (*importer.Importer, error) {
+	include, err := compile(o.include)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude, err := compile(o.exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	i := importer.New(nil).
+		WithDir(o.dir).
+		WithEnv(o.env()).
+		WithBuildFlags(o.buildFlags()).
+		WithFromType(o.fromType).
+		WithUnexported(o.unexported).
+		WithAllowErrors(o.allowErrors).
+		WithPatterns(include, exclude).
+		WithWarnings(os.Stderr)
+
+	if o.verbose {
+		i.WithDiagnostics(os.Stderr)
+	}
+
+	return i, nil
+}
+
+func newQualifier(importPath, fileName, mockPackage string, o importerOptions) (importer.Qualifier, error) {
+	b := (&importer.QualifierBuilder{}).WithEnv(o.env()).WithBuildFlags(o.buildFlags())
 
 	if fileName != "" {
 		b = b.WithPackageDir(filepath.Dir(fileName))
 	}
 
-	qf, err := b.WithPackageName(mockPackage).
+	return b.WithPackageName(mockPackage).
 		WithPackagePath(importPath).
 		Build()
+}
+
+// generate generates the mocks of the package with a qualifier for the output file.
+func generate(g *generator.Generator, i *importer.Importer, importPath string, o generator.Options, interfaces []string) ([]byte, error) {
+	qf, err := newQualifier(importPath, o.FileName, o.MockPackage, importOptions)
 	if err != nil {
 		return nil, err
 	}
 
-	return importer.New(qf), nil
+	i.WithQualifier(qf)
+
+	return g.Generate(importPath, o, interfaces...)
+}
+
+// splitArgs splits the arguments into the import paths and the interfaces following them.
+// The first argument is an import path, the next ones are interfaces from the first identifier on.
+func splitArgs(args []string) (importPaths, interfaces []string) {
+	n := 1
+	for n < len(args) && !isInterface(args[n]) {
+		n++
+	}
+
+	return args[:n], args[n:]
+}
+
+// several reports whether the arguments may match several packages, whose mocks go to the output directory
+// or file pattern: several import paths, a pattern with ..., or an output file pattern.
+func several(importPaths []string, out string) bool {
+	if len(importPaths) > 1 || strings.Contains(out, "{") {
+		return true
+	}
+
+	return slices.ContainsFunc(importPaths, func(p string) bool { return strings.Contains(p, "...") })
+}
+
+// isInterface reports whether the argument is an interface name, possibly instantiated.
+func isInterface(arg string) bool {
+	name, _, _ := strings.Cut(arg, "[")
+	return token.IsIdentifier(name)
+}
+
+// outFile returns the output file for the package: the file pattern with the package directory and name,
+// or a file in the output directory under the directory of the package relative to its module root,
+// or under its import path out of a module, so that packages with the same name do not collide.
+func outFile(out string, pkg *packages.Package) string {
+	if strings.Contains(out, "{") {
+		return strings.NewReplacer("{dir}", pkg.Dir, "{name}", pkg.Name).Replace(out)
+	}
+
+	dir := filepath.FromSlash(pkg.PkgPath)
+	if m := pkg.Module; m != nil && m.Dir != "" {
+		if rel, err := filepath.Rel(m.Dir, pkg.Dir); err == nil && filepath.IsLocal(rel) {
+			dir = rel
+		}
+	}
+
+	return filepath.Join(out, dir, "mock.go")
+}
+
+func compile(patterns []string) ([]*regexp.Regexp, error) {
+	var r []*regexp.Regexp
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+
+		r = append(r, re)
+	}
+
+	return r, nil
 }
 
 func newTemplate(fileName string) (*template.Template, error) {
@@ -79,6 +312,10 @@ func newTemplate(fileName string) (*template.Template, error) {
 		return template.Default()
 	}
 
+	if name, ok := strings.CutPrefix(fileName, "builtin:"); ok {
+		return template.Builtin(name)
+	}
+
 	return template.New(fileName)
 }
 
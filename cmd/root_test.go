@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeveral(t *testing.T) {
+	tests := []struct {
+		name        string
+		importPaths []string
+		out         string
+		want        bool
+	}{
+		{
+			name:        "single import path",
+			importPaths: []string{"./store"},
+			out:         "mock.go",
+			want:        false,
+		}, {
+			name:        "several import paths",
+			importPaths: []string{"./store", "./client"},
+			out:         "mocks",
+			want:        true,
+		}, {
+			name:        "pattern",
+			importPaths: []string{"./sto..."},
+			out:         "mocks",
+			want:        true,
+		}, {
+			name:        "file pattern",
+			importPaths: []string{"./store"},
+			out:         "{dir}/mock_test.go",
+			want:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, several(tt.importPaths, tt.out))
+		})
+	}
+}
+
+func TestExecute_out(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n\ngo 1.22\n"), 0666))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "store"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "store", "store.go"),
+		[]byte("package store\n\ntype Store interface{ Get(id string) error }\n"), 0666))
+
+	tests := []struct {
+		name string
+		args []string
+		out  string
+		want string
+	}{
+		{
+			name: "file",
+			args: []string{"./store"},
+			out:  filepath.Join(dir, "store.go"),
+			want: filepath.Join(dir, "store.go"),
+		}, {
+			name: "directory with a pattern matching one package",
+			args: []string{"./sto..."},
+			out:  filepath.Join(dir, "mocks"),
+			want: filepath.Join(dir, "mocks", "store", "mock.go"),
+		}, {
+			name: "file pattern with one package",
+			args: []string{"./store"},
+			out:  "{dir}/mock_test.go",
+			want: filepath.Join(dir, "store", "mock_test.go"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd.SetArgs(append(tt.args, "--include", ".", "--dir", dir, "--out", tt.out))
+			require.NoError(t, cmd.Execute())
+
+			assert.FileExists(t, tt.want)
+		})
+	}
+}
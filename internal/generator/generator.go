@@ -2,8 +2,10 @@ package generator
 
 import (
 	"bytes"
+	"cmp"
 	"fmt"
 	"io"
+	"maps"
 	"strings"
 
 	"golang.org/x/tools/imports"
@@ -17,16 +19,25 @@ type Parser interface {
 }
 
 // Renderer allows mock implementation rendering.
+// Reserved returns the names used by the rendered method besides the receiver, the imported packages
+// and the type parameters, which parameters are renamed not to shadow.
 type Renderer interface {
 	Render(w io.Writer, p internal.Package, substitutions map[string]string) error
+	Reserved(m internal.Method) ([]string, error)
 }
 
 // Options represent a set of options to use when generating mock implementations.
+// MockNames are keyed by interface name, or by instantiation for instances of generic interfaces,
+// which fall back to the default name of their mock.
+// With SkipEmpty, no code is generated for a package without interfaces to mock.
+// With NameResults, unnamed and blank results are named r0, r1, ... as parameters are named p0, p1, ...
 type Options struct {
 	MockPackage   string
 	MockNames     map[string]string
 	FileName      string
 	Substitutions map[string]string
+	SkipEmpty     bool
+	NameResults   bool
 }
 
 // Generator generates mock implementations of Go interfaces.
@@ -47,6 +58,10 @@ func (g *Generator) Generate(importPath string, options Options, interfaces ...s
 		return nil, err
 	}
 
+	if options.SkipEmpty && len(pkg.Interfaces) == 0 {
+		return nil, nil
+	}
+
 	var b bytes.Buffer
 	if err := g.renderer.Render(&b, pkg, options.Substitutions); err != nil {
 		return nil, err
@@ -70,24 +85,123 @@ func (g *Generator) parse(importPath string, options Options, interfaces ...stri
 		pkg.Name = options.MockPackage
 	}
 
+	mocks := make(map[string]string)
+
 	for i, iface := range pkg.Interfaces {
-		if options.MockNames[iface.Name] != "" {
-			pkg.Interfaces[i].Name = options.MockNames[iface.Name]
+		source := cmp.Or(iface.Instantiation, iface.Name)
+		if n := cmp.Or(options.MockNames[source], options.MockNames[iface.Name]); n != "" {
+			pkg.Interfaces[i].Name = n
 		}
 
+		if other, ok := mocks[pkg.Interfaces[i].Name]; ok {
+			return internal.Package{}, fmt.Errorf("the mocks of %s and %s are both named %s, give them different mock names",
+				other, source, pkg.Interfaces[i].Name)
+		}
+
+		mocks[pkg.Interfaces[i].Name] = source
+
 		for _, m := range iface.Methods {
 			if m.Variadic {
 				l := len(m.Parameters)
 				m.Parameters[l-1].Type = strings.Replace(m.Parameters[l-1].Type, "[]", "...", 1)
 			}
 
-			if len(m.Parameters) > 0 && m.Parameters[0].Name == "" {
-				for i := range m.Parameters {
-					m.Parameters[i].Name = fmt.Sprintf("p%d", i)
-				}
+			used := names(m.Parameters, m.Results)
+			nameBlank(m.Parameters, "p", used)
+			if options.NameResults {
+				nameBlank(m.Results, "r", used)
 			}
+
+			reserved, err := g.reserved(pkg, pkg.Interfaces[i], m, options)
+			if err != nil {
+				return internal.Package{}, err
+			}
+
+			rename(reserved, m.Parameters, m.Results)
 		}
 	}
 
 	return pkg, nil
 }
+
+// reserved returns the names that parameters must not shadow in the methods of the mock:
+// the receiver, the names reserved by the renderer, the imported packages and the type parameters.
+func (g *Generator) reserved(pkg internal.Package, iface internal.Interface, m internal.Method, options Options) (map[string]bool, error) {
+	r := map[string]bool{cmp.Or(options.Substitutions[iface.Name+"Receiver"], "m"): true}
+
+	names, err := g.renderer.Reserved(m)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range names {
+		r[n] = true
+	}
+
+	for _, i := range pkg.Imports {
+		r[cmp.Or(i.Alias, i.Name)] = true
+	}
+
+	for _, p := range iface.TypeParameters {
+		r[p.Name] = true
+	}
+
+	return r, nil
+}
+
+// names returns the names of the variables of the lists.
+func names(lists ...[]internal.Variable) map[string]bool {
+	r := make(map[string]bool)
+	for _, vars := range lists {
+		for _, v := range vars {
+			r[v.Name] = true
+		}
+	}
+
+	return r
+}
+
+// nameBlank names the blank and unnamed variables with the prefix followed by their index,
+// or by the next free index if the name is used, and marks the names used.
+func nameBlank(vars []internal.Variable, prefix string, used map[string]bool) {
+	for i, v := range vars {
+		if v.Name != "" && v.Name != "_" {
+			continue
+		}
+
+		n := i
+		for used[fmt.Sprintf("%s%d", prefix, n)] {
+			n++
+		}
+
+		vars[i].Name = fmt.Sprintf("%s%d", prefix, n)
+		used[vars[i].Name] = true
+	}
+}
+
+// rename renames the parameters and results with reserved names by appending the first number from 2 on
+// that makes their names unique.
+func rename(reserved map[string]bool, lists ...[]internal.Variable) {
+	used := names(lists...)
+	maps.Copy(used, reserved)
+
+	for _, vars := range lists {
+		for i, v := range vars {
+			if reserved[v.Name] {
+				vars[i].Name = unique(v.Name, used)
+			}
+		}
+	}
+}
+
+// unique returns the name, or the name followed by the first number from 2 on if it is used, and marks it used.
+func unique(name string, used map[string]bool) string {
+	r := name
+	for n := 2; used[r]; n++ {
+		r = fmt.Sprintf("%s%d", name, n)
+	}
+
+	used[r] = true
+
+	return r
+}
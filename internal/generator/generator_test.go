@@ -27,6 +27,12 @@ func (m *renderer) Render(w io.Writer, p internal.Package, substitutions map[str
 	return args.Error(0)
 }
 
+// Reserved is a mocked method on renderer.
+func (m *renderer) Reserved(method internal.Method) ([]string, error) {
+	args := m.Called(method)
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func TestGenerator_Generate(t *testing.T) {
 	const importPath = "golang.org/fake/a"
 
@@ -71,7 +77,7 @@ func TestGenerator_Generate(t *testing.T) {
 				Methods: []internal.Method{
 					{
 						Name:       "F",
-						Parameters: []internal.Variable{{Name: "b", Type: "B"}, {Name: "args", Type: "...string"}},
+						Parameters: []internal.Variable{{Name: "b", Type: "B"}, {Name: "args2", Type: "...string"}},
 						Results:    []internal.Variable{{Type: "error"}},
 						Variadic:   true,
 					},
@@ -95,7 +101,7 @@ func TestGenerator_Generate(t *testing.T) {
 				Methods: []internal.Method{
 					{
 						Name:       "F",
-						Parameters: []internal.Variable{{Name: "b", Type: "B"}, {Name: "args", Type: "...string"}},
+						Parameters: []internal.Variable{{Name: "b", Type: "B"}, {Name: "args2", Type: "...string"}},
 						Results:    []internal.Variable{{Type: "error"}},
 						Variadic:   true,
 					},
@@ -148,6 +154,183 @@ func TestGenerator_Generate(t *testing.T) {
 			},
 			want:      []byte("package a\n"),
 			assertion: assert.NoError,
+		}, {
+			name: "name collisions",
+			args: args{importPath: importPath, options: Options{Substitutions: map[string]string{"I2Receiver": "r"}}},
+			expect: func(p *parser, r *renderer) {
+				newMethod := func() internal.Method {
+					return internal.Method{
+						Name: "F",
+						Parameters: []internal.Variable{
+							{Name: "m", Type: "int"},
+							{Name: "m2", Type: "int"},
+							{Name: "r", Type: "int"},
+							{Name: "io", Type: "io.Reader"},
+							{Name: "mock", Type: "string"},
+							{Name: "T", Type: "T"},
+						},
+					}
+				}
+				pkg := internal.Package{
+					Name:    "a",
+					Imports: []internal.Import{{Name: "io", Path: "io"}},
+					Interfaces: []internal.Interface{
+						{
+							Name:           "I1",
+							TypeParameters: []internal.TypeParameter{{Name: "T", Constraint: "any"}},
+							Methods:        []internal.Method{newMethod()},
+						},
+						{Name: "I2", Methods: []internal.Method{newMethod()}},
+					},
+				}
+				p.On("Parse", importPath, []string(nil)).Return(pkg, nil).Once()
+
+				want := internal.Package{
+					Name:    "a",
+					Imports: []internal.Import{{Name: "io", Path: "io"}},
+					Interfaces: []internal.Interface{
+						{
+							Name:           "I1",
+							TypeParameters: []internal.TypeParameter{{Name: "T", Constraint: "any"}},
+							Methods: []internal.Method{{
+								Name: "F",
+								Parameters: []internal.Variable{
+									{Name: "m3", Type: "int"},
+									{Name: "m2", Type: "int"},
+									{Name: "r", Type: "int"},
+									{Name: "io2", Type: "io.Reader"},
+									{Name: "mock2", Type: "string"},
+									{Name: "T2", Type: "T"},
+								},
+							}},
+						},
+						{
+							Name: "I2",
+							Methods: []internal.Method{{
+								Name: "F",
+								Parameters: []internal.Variable{
+									{Name: "m", Type: "int"},
+									{Name: "m2", Type: "int"},
+									{Name: "r2", Type: "int"},
+									{Name: "io2", Type: "io.Reader"},
+									{Name: "mock2", Type: "string"},
+									{Name: "T", Type: "T"},
+								},
+							}},
+						},
+					},
+				}
+				r.On("Render", mock.Anything, want, map[string]string{"I2Receiver": "r"}).Run(func(args mock.Arguments) {
+					_, _ = args.Get(0).(*bytes.Buffer).WriteString("package a")
+				}).Return(nil).Once()
+			},
+			want:      []byte("package a\n"),
+			assertion: assert.NoError,
+		}, {
+			name: "blank names",
+			args: args{importPath: importPath, options: Options{NameResults: true}},
+			expect: func(p *parser, r *renderer) {
+				pkg := internal.Package{
+					Name: "a",
+					Interfaces: []internal.Interface{{
+						Name: "I",
+						Methods: []internal.Method{{
+							Name:       "F",
+							Parameters: []internal.Variable{{Name: "_", Type: "int"}, {Name: "ctx", Type: "context.Context"}, {Name: "_", Type: "bool"}},
+							Results:    []internal.Variable{{Name: "r1", Type: "int"}, {Name: "_", Type: "error"}},
+						}, {
+							Name:    "G",
+							Results: []internal.Variable{{Type: "int"}, {Type: "error"}},
+						}, {
+							Name:       "A",
+							Parameters: []internal.Variable{{Name: "_", Type: "int"}},
+							Results:    []internal.Variable{{Name: "p0", Type: "error"}},
+						}, {
+							Name:       "B",
+							Parameters: []internal.Variable{{Name: "r0", Type: "int"}},
+							Results:    []internal.Variable{{Type: "error"}},
+						}},
+					}},
+				}
+				p.On("Parse", importPath, []string(nil)).Return(pkg, nil).Once()
+
+				want := internal.Package{
+					Name: "a",
+					Interfaces: []internal.Interface{{
+						Name: "I",
+						Methods: []internal.Method{{
+							Name:       "F",
+							Parameters: []internal.Variable{{Name: "p0", Type: "int"}, {Name: "ctx", Type: "context.Context"}, {Name: "p2", Type: "bool"}},
+							Results:    []internal.Variable{{Name: "r1", Type: "int"}, {Name: "r2", Type: "error"}},
+						}, {
+							Name:    "G",
+							Results: []internal.Variable{{Name: "r0", Type: "int"}, {Name: "r1", Type: "error"}},
+						}, {
+							Name:       "A",
+							Parameters: []internal.Variable{{Name: "p1", Type: "int"}},
+							Results:    []internal.Variable{{Name: "p0", Type: "error"}},
+						}, {
+							Name:       "B",
+							Parameters: []internal.Variable{{Name: "r0", Type: "int"}},
+							Results:    []internal.Variable{{Name: "r1", Type: "error"}},
+						}},
+					}},
+				}
+				r.On("Render", mock.Anything, want, map[string]string(nil)).Run(func(args mock.Arguments) {
+					_, _ = args.Get(0).(*bytes.Buffer).WriteString("package a")
+				}).Return(nil).Once()
+			},
+			want:      []byte("package a\n"),
+			assertion: assert.NoError,
+		}, {
+			name: "instantiation names",
+			args: args{importPath: importPath, options: Options{MockNames: map[string]string{"Repo[*User]": "UserPtrRepo"}}},
+			expect: func(p *parser, r *renderer) {
+				pkg := internal.Package{
+					Name: "a",
+					Interfaces: []internal.Interface{
+						{Name: "UserRepo", Instantiation: "Repo[User]"},
+						{Name: "UserRepo", Instantiation: "Repo[*User]"},
+					},
+				}
+				p.On("Parse", importPath, []string(nil)).Return(pkg, nil).Once()
+
+				want := internal.Package{
+					Name: "a",
+					Interfaces: []internal.Interface{
+						{Name: "UserRepo", Instantiation: "Repo[User]"},
+						{Name: "UserPtrRepo", Instantiation: "Repo[*User]"},
+					},
+				}
+				r.On("Render", mock.Anything, want, map[string]string(nil)).Run(func(args mock.Arguments) {
+					_, _ = args.Get(0).(*bytes.Buffer).WriteString("package a")
+				}).Return(nil).Once()
+			},
+			want:      []byte("package a\n"),
+			assertion: assert.NoError,
+		}, {
+			name: "duplicate mock names",
+			args: args{importPath: importPath, options: Options{MockNames: map[string]string{"UserRepo": "Users"}}},
+			expect: func(p *parser, r *renderer) {
+				pkg := internal.Package{
+					Name: "a",
+					Interfaces: []internal.Interface{
+						{Name: "UserRepo", Instantiation: "Repo[User]"},
+						{Name: "UserRepo", Instantiation: "Repo[*User]"},
+					},
+				}
+				p.On("Parse", importPath, []string(nil)).Return(pkg, nil).Once()
+			},
+			assertion: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.EqualError(t, err, "the mocks of Repo[User] and Repo[*User] are both named Users, give them different mock names")
+			},
+		}, {
+			name: "skip empty",
+			args: args{importPath: importPath, options: Options{SkipEmpty: true}},
+			expect: func(p *parser, r *renderer) {
+				p.On("Parse", importPath, []string(nil)).Return(internal.Package{Name: "a"}, nil).Once()
+			},
+			assertion: assert.NoError,
 		}, {
 			name: "parse error",
 			expect: func(p *parser, r *renderer) {
@@ -162,6 +345,14 @@ func TestGenerator_Generate(t *testing.T) {
 				r.On("Render", mock.Anything, pkgA, map[string]string(nil)).Return(assert.AnError).Once()
 			},
 			assertion: assert.Error,
+		}, {
+			name: "reserved names error",
+			expect: func(p *parser, r *renderer) {
+				p.On("Parse", "", []string(nil)).Return(newPkg(), nil).Once()
+
+				r.On("Reserved", mock.Anything).Return([]string(nil), assert.AnError).Once()
+			},
+			assertion: assert.Error,
 		}, {
 			name: "format error",
 			expect: func(p *parser, r *renderer) {
@@ -177,6 +368,7 @@ func TestGenerator_Generate(t *testing.T) {
 			p := new(parser)
 			r := new(renderer)
 			tt.expect(p, r)
+			r.On("Reserved", mock.Anything).Return([]string{"args", "mock"}, nil).Maybe()
 
 			g := New(p, r)
 
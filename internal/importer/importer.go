@@ -1,8 +1,15 @@
 package importer
 
 import (
+	"errors"
 	"fmt"
+	"go/ast"
+	"go/token"
 	"go/types"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
 
 	"golang.org/x/tools/go/packages"
 
@@ -12,99 +19,627 @@ import (
 // Qualifier controls how named package-level objects are printed.
 type Qualifier interface {
 	Qualify(pkg *types.Package) string
+	Local(pkg *types.Package) bool
+	Importable(path string) bool
 	Imports() []internal.Import
 }
 
 // Importer resolves import paths to packages.
 type Importer struct {
-	qualifier Qualifier
-	config    *packages.Config
+	qualifier   Qualifier
+	config      *packages.Config
+	fromType    bool
+	unexported  bool
+	allowErrors bool
+	diagnostics io.Writer
+	warnings    io.Writer
+	include     []*regexp.Regexp
+	exclude     []*regexp.Regexp
+	packages    map[string]*packages.Package
+	files       map[string]bool
+	matched     map[*regexp.Regexp]bool
+	fset        *token.FileSet
+	docs        map[token.Pos]string
 }
 
 // New returns an Importer for importing directly from the source.
+// The qualifier may be nil if it is set with WithQualifier before parsing.
 func New(qf Qualifier) *Importer {
-	return &Importer{qf, &packages.Config{Mode: packages.NeedTypes | packages.NeedImports}}
+	return &Importer{
+		qualifier: qf,
+		config: &packages.Config{
+			Mode: packages.NeedName | packages.NeedFiles | packages.NeedTypes | packages.NeedImports | packages.NeedSyntax |
+				packages.NeedModule,
+		},
+		packages: make(map[string]*packages.Package),
+		files:    make(map[string]bool),
+		matched:  make(map[*regexp.Regexp]bool),
+	}
+}
+
+// WithQualifier sets the qualifier to use for the packages parsed next.
+func (im *Importer) WithQualifier(qf Qualifier) *Importer {
+	im.qualifier = qf
+	return im
+}
+
+// WithBuildFlags sets the build flags, such as -tags, to use when loading packages.
+func (im *Importer) WithBuildFlags(flags []string) *Importer {
+	im.config.BuildFlags = flags
+	return im
+}
+
+// WithEnv sets the environment, such as GOOS and GOARCH, to use when loading packages.
+// A nil environment means the environment of the current process.
+func (im *Importer) WithEnv(env []string) *Importer {
+	im.config.Env = env
+	return im
+}
+
+// WithDir sets the directory in which to load packages. An empty directory means the current one.
+func (im *Importer) WithDir(dir string) *Importer {
+	im.config.Dir = dir
+	return im
+}
+
+// WithFromType allows struct types given by name to be mocked from the method sets of their pointer types.
+func (im *Importer) WithFromType(fromType bool) *Importer {
+	im.fromType = fromType
+	return im
+}
+
+// WithUnexported allows unexported interfaces to be mocked, which is only possible in their own package.
+func (im *Importer) WithUnexported(unexported bool) *Importer {
+	im.unexported = unexported
+	return im
+}
+
+// WithAllowErrors allows packages with errors to be parsed. Interfaces with invalid types are then skipped
+// with a warning, or fail if they are given by name.
+func (im *Importer) WithAllowErrors(allowErrors bool) *Importer {
+	im.allowErrors = allowErrors
+	return im
+}
+
+// WithDiagnostics sets the writer of the module, version and directory each interface is loaded from.
+func (im *Importer) WithDiagnostics(w io.Writer) *Importer {
+	im.diagnostics = w
+	return im
+}
+
+// WithWarnings sets the writer of the reasons why interfaces that are not given by name are skipped.
+func (im *Importer) WithWarnings(w io.Writer) *Importer {
+	im.warnings = w
+	return im
+}
+
+// WithPatterns sets the patterns of the interface names to include and to exclude.
+// Included interfaces are added to the ones given by name.
+func (im *Importer) WithPatterns(include, exclude []*regexp.Regexp) *Importer {
+	im.include = include
+	im.exclude = exclude
+	return im
+}
+
+// Load loads the packages matching the given patterns at once, so that parsing them does not load them again.
+func (im *Importer) Load(patterns ...string) ([]*packages.Package, error) {
+	pkgs, err := packages.Load(im.config, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no packages matching %s", strings.Join(patterns, " "))
+	}
+
+	for _, pkg := range pkgs {
+		im.packages[pkg.PkgPath] = pkg
+	}
+
+	return pkgs, nil
 }
 
 // Parse returns the package for the given import path with filtered interfaces.
 func (im *Importer) Parse(importPath string, interfaces ...string) (internal.Package, error) {
-	pkgs, err := packages.Load(im.config, importPath)
-	if err != nil {
-		return internal.Package{}, err
+	pkg, ok := im.packages[importPath]
+	if !ok {
+		pkgs, err := packages.Load(im.config, importPath)
+		if err != nil {
+			return internal.Package{}, err
+		}
+
+		if len(pkgs) != 1 {
+			return internal.Package{}, fmt.Errorf("package %s not found", importPath)
+		}
+
+		pkg = pkgs[0]
 	}
 
-	if len(pkgs) != 1 {
-		return internal.Package{}, fmt.Errorf("package %s not found", importPath)
+	if errs := loadErrors(pkg); len(errs) > 0 && !im.allowErrors {
+		return internal.Package{}, errors.Join(errs...)
 	}
 
-	pkg := pkgs[0]
+	return im.toPackage(pkg, interfaces)
+}
+
+// module describes the module of the package with its version and replacement.
+func module(pkg *packages.Package) string {
+	m := pkg.Module
+	if m == nil {
+		return "no module"
+	}
 
-	if len(pkg.Errors) > 0 {
-		return internal.Package{}, pkg.Errors[0]
+	r := "module " + m.Path
+	if m.Version != "" {
+		r += "@" + m.Version
 	}
 
-	return im.toPackage(pkg.Types, interfaces)
+	if m.Main {
+		r += " (main)"
+	}
+
+	if m.Replace != nil {
+		r += " => " + m.Replace.Path
+		if m.Replace.Version != "" {
+			r += "@" + m.Replace.Version
+		}
+	}
+
+	return r
 }
 
-func (im *Importer) toPackage(pkg *types.Package, interfaceNames []string) (r internal.Package, err error) {
+// loadErrors returns the errors of the package and of its dependencies.
+func loadErrors(pkg *packages.Package) []error {
+	var errs []error
+	packages.Visit([]*packages.Package{pkg}, nil, func(p *packages.Package) {
+		for _, err := range p.Errors {
+			// The compiler output reported by go list without position is repeated by the type errors.
+			if err.Kind == packages.ListError && err.Pos == "" && len(p.TypeErrors) > 0 {
+				continue
+			}
+
+			errs = append(errs, err)
+		}
+	})
+
+	return errs
+}
+
+func (im *Importer) toPackage(pkg *packages.Package, interfaceNames []string) (r internal.Package, err error) {
 	r = internal.Package{
-		Name: pkg.Name(),
+		Name: pkg.Types.Name(),
+		Path: pkg.Types.Path(),
 	}
 
+	im.fset = pkg.Fset
+	im.docs = docs(pkg.Syntax)
+
 	r.Interfaces, err = im.lookup(pkg, interfaceNames)
 	if err != nil {
 		return internal.Package{}, err
 	}
 
+	for _, iface := range r.Interfaces {
+		if err := im.diagnose("%s.%s: %s, directory %s\n", pkg.PkgPath, iface.Name, module(pkg), pkg.Dir); err != nil {
+			return internal.Package{}, err
+		}
+	}
+
 	r.Imports = im.qualifier.Imports()
 
 	return r, nil
 }
 
-func (im *Importer) lookup(pkg *types.Package, interfaceNames []string) ([]internal.Interface, error) {
-	names := interfaceNames
-	if len(names) == 0 {
-		names = pkg.Scope().Names()
+func (im *Importer) lookup(pkg *packages.Package, interfaceNames []string) ([]internal.Interface, error) {
+	names, err := im.filter(pkg.Types, interfaceNames)
+	if err != nil {
+		return nil, err
+	}
+
+	explicit := make(map[string]bool)
+	for _, n := range interfaceNames {
+		explicit[n] = true
 	}
 
 	var ifaces []internal.Interface
 	for _, n := range names {
-		obj := pkg.Scope().Lookup(n)
+		if strings.Contains(n, "[") {
+			name, t, err := im.instantiate(pkg, n)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := im.checkVisibility(name, t); err != nil {
+				return nil, err
+			}
+
+			iface := im.toInterface(name, t)
+			iface.Instantiation = n
+			ifaces = append(ifaces, iface)
+			continue
+		}
+
+		obj := pkg.Types.Scope().Lookup(n)
 
 		if obj == nil {
-			return nil, fmt.Errorf("interface %s missing", n)
+			err := fmt.Errorf("%s: interface %s missing", pkg.Types.Path(), n)
+			return nil, errors.Join(append([]error{err}, loadErrors(pkg)...)...)
+		}
+
+		_, ok := obj.(*types.TypeName)
+
+		if ok && (types.IsInterface(obj.Type()) || explicit[n]) && !valid(obj.Type()) {
+			err := fmt.Errorf("%s: %s has invalid types", im.fset.Position(obj.Pos()), n)
+			if len(pkg.TypeErrors) > 0 {
+				err = fmt.Errorf("%w: %w", err, pkg.TypeErrors[0])
+			}
+
+			// A file loaded on its own may refer to declarations of other files of its package.
+			if (im.allowErrors || im.files[pkg.PkgPath]) && !explicit[n] {
+				if err := im.warn("skipping %s.%s: %v\n", pkg.PkgPath, n, err); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+
+			return nil, err
+		}
+
+		if ok && (types.IsInterface(obj.Type()) || explicit[n]) {
+			if err := im.checkVisibility(n, obj.Type()); err != nil {
+				if explicit[n] {
+					return nil, err
+				}
+
+				if err := im.warn("skipping %s.%s: %v\n", pkg.PkgPath, n, err); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
 		}
 
-		if _, ok := obj.(*types.TypeName); ok && types.IsInterface(obj.Type()) {
-			iface := obj.Type().Underlying().(*types.Interface).Complete()
-			ifaces = append(ifaces, im.toInterface(n, iface))
-		} else if len(interfaceNames) > 0 {
-			return nil, fmt.Errorf("%s should be an interface, was %s", n, obj.Type())
+		switch {
+		case ok && types.IsInterface(obj.Type()):
+			ifaces = append(ifaces, im.toInterface(n, obj.Type()))
+		case ok && im.fromType && isStruct(obj.Type()) && explicit[n]:
+			ifaces = append(ifaces, im.fromMethodSet(n, obj.Type()))
+		case ok && isFunc(obj.Type()) && explicit[n]:
+			ifaces = append(ifaces, im.fromFunc(n, obj.Type()))
+		case explicit[n]:
+			return nil, fmt.Errorf("%s: %s should be an interface, was %s", im.fset.Position(obj.Pos()), n, obj.Type())
 		}
 	}
 
 	return ifaces, nil
 }
 
-func (im *Importer) toInterface(name string, iface *types.Interface) internal.Interface {
+// diagnose writes the diagnostic to the diagnostics writer, if any.
+func (im *Importer) diagnose(format string, a ...any) error {
+	return printf(im.diagnostics, format, a...)
+}
+
+// warn writes the warning to the warnings writer, if any.
+func (im *Importer) warn(format string, a ...any) error {
+	return printf(im.warnings, "warning: "+format, a...)
+}
+
+// printf writes the formatted text to the writer, if not nil.
+func printf(w io.Writer, format string, a ...any) error {
+	if w == nil {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(w, format, a...)
+	return err
+}
+
+// checkVisibility returns an error if a method to mock of the named type is unexported in another package,
+// or refers to a type that the generated code cannot use: an unexported type of another package,
+// or a type of an internal package it cannot import.
+func (im *Importer) checkVisibility(name string, t types.Type) error {
+	for _, m := range methods(t) {
+		if !m.Exported() && m.Pkg() != nil && !im.qualifier.Local(m.Pkg()) {
+			return fmt.Errorf("%s: method %s of %s is unexported, so only code of %s can implement it",
+				im.fset.Position(m.Pos()), m.Name(), name, m.Pkg().Path())
+		}
+
+		var err error
+
+		walk(m.Type(), func(t types.Type) bool {
+			if _, ok := t.(*types.TypeParam); ok {
+				return true
+			}
+
+			named, ok := t.(interface{ Obj() *types.TypeName })
+			if !ok || named.Obj().Pkg() == nil || im.qualifier.Local(named.Obj().Pkg()) {
+				return true
+			}
+
+			obj := named.Obj()
+			pos := im.fset.Position(m.Pos())
+
+			switch {
+			case !obj.Exported():
+				err = fmt.Errorf("%s: method %s of %s refers to %s.%s, which is unexported", pos, m.Name(), name, obj.Pkg().Path(), obj.Name())
+			case !im.qualifier.Importable(obj.Pkg().Path()):
+				err = fmt.Errorf("%s: method %s of %s refers to %s.%s, whose internal package cannot be imported by the generated code",
+					pos, m.Name(), name, obj.Pkg().Path(), obj.Name())
+			}
+
+			return err == nil
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// filter returns the given interface names with the names of the interfaces matching the include patterns,
+// without the names matching the exclude patterns. All names of the package are used if none is given or included.
+// Unexported names are only used in the unexported mode when the package is the package of the generated code.
+func (im *Importer) filter(pkg *types.Package, interfaceNames []string) ([]string, error) {
+	scope := pkg.Scope()
+
+	var candidates []string
+	for _, n := range scope.Names() {
+		if token.IsExported(n) || im.unexported {
+			candidates = append(candidates, n)
+		}
+	}
+
+	names := slices.Clone(interfaceNames)
+	if len(names) == 0 && len(im.include) == 0 {
+		names = slices.Clone(candidates)
+	}
+
+	for _, re := range im.include {
+		var matched bool
+
+		for _, n := range candidates {
+			if _, ok := scope.Lookup(n).(*types.TypeName); ok && types.IsInterface(scope.Lookup(n).Type()) && re.MatchString(n) {
+				matched = true
+
+				if !slices.Contains(names, n) {
+					names = append(names, n)
+				}
+			}
+		}
+
+		if !matched && len(im.packages) <= 1 {
+			return nil, fmt.Errorf("%s: no interface matches the include pattern %s", pkg.Path(), re)
+		}
+
+		im.matched[re] = im.matched[re] || matched
+	}
+
+	for _, re := range im.exclude {
+		l := len(names)
+
+		names = slices.DeleteFunc(names, re.MatchString)
+
+		if len(names) == l && len(im.packages) <= 1 {
+			return nil, fmt.Errorf("%s: no interface matches the exclude pattern %s", pkg.Path(), re)
+		}
+
+		im.matched[re] = im.matched[re] || len(names) < l
+	}
+
+	for _, n := range names {
+		generic, _, _ := strings.Cut(n, "[")
+		if token.IsExported(generic) || !slices.Contains(interfaceNames, n) && !types.IsInterface(scope.Lookup(n).Type()) {
+			continue
+		}
+
+		if !im.unexported {
+			return nil, fmt.Errorf("%s: %s is unexported, which requires the unexported mode", pkg.Path(), generic)
+		}
+
+		if !im.qualifier.Local(pkg) {
+			return nil, fmt.Errorf("%s: unexported %s can only be mocked in its own package", pkg.Path(), generic)
+		}
+	}
+
+	return names, nil
+}
+
+// CheckPatterns returns an error if an include or exclude pattern has matched no interface of the parsed packages.
+// When several packages are loaded, a pattern only has to match interfaces of one of them.
+func (im *Importer) CheckPatterns() error {
+	for _, re := range slices.Concat(im.include, im.exclude) {
+		if !im.matched[re] {
+			return fmt.Errorf("no interface matches the pattern %s", re)
+		}
+	}
+
+	return nil
+}
+
+func (im *Importer) toInterface(name string, t types.Type) internal.Interface {
+	iface := t.Underlying().(*types.Interface).Complete()
 	n := iface.NumMethods()
 
+	obj := t.(interface{ Obj() *types.TypeName }).Obj()
+
 	r := internal.Interface{
-		Name: name,
+		Name:           name,
+		Kind:           internal.KindInterface,
+		ImportPath:     path(obj.Pkg()),
+		Doc:            im.docs[obj.Pos()],
+		TypeParameters: im.toTypeParameters(t),
+		Position:       im.fset.Position(obj.Pos()),
+	}
+	r.Type = im.typeString(t, r.TypeParameters)
+
+	// Embedded interfaces are named as in the source package, not as in the generated code.
+	pkg := obj.Pkg()
+	qf := func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+
+		return p.Name()
+	}
+
+	var embeds []types.Type
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		e := iface.EmbeddedType(i)
+		if o, ok := e.(interface{ Obj() *types.TypeName }); ok && types.IsInterface(e) {
+			embeds = append(embeds, e)
+			r.Embeds = append(r.Embeds, internal.Embed{Name: types.TypeString(e, qf), Path: path(o.Obj().Pkg())})
+		}
 	}
 
 	for i := 0; i < n; i++ {
-		r.Methods = append(r.Methods, im.toMethod(iface.Method(i)))
+		m := im.toMethod(iface.Method(i))
+		m.Origin = origin(iface, iface.Method(i), embeds, qf)
+		r.Methods = append(r.Methods, m)
+	}
+
+	return r
+}
+
+func (im *Importer) fromMethodSet(name string, t types.Type) internal.Interface {
+	t = types.Unalias(t)
+	obj := t.(*types.Named).Obj()
+
+	r := internal.Interface{
+		Name:           name,
+		Kind:           internal.KindStruct,
+		ImportPath:     path(obj.Pkg()),
+		Doc:            im.docs[obj.Pos()],
+		TypeParameters: im.toTypeParameters(t),
+		Position:       im.fset.Position(obj.Pos()),
+	}
+	r.Type = im.typeString(t, r.TypeParameters)
+
+	ms := types.NewMethodSet(types.NewPointer(t))
+	for i := 0; i < ms.Len(); i++ {
+		if f := ms.At(i).Obj().(*types.Func); f.Exported() {
+			r.Methods = append(r.Methods, im.toMethod(f))
+		}
+	}
+
+	return r
+}
+
+func (im *Importer) fromFunc(name string, t types.Type) internal.Interface {
+	obj := t.(interface{ Obj() *types.TypeName }).Obj()
+
+	m := im.toSignature("Func", t.Underlying().(*types.Signature))
+	m.Position = im.fset.Position(obj.Pos())
+
+	r := internal.Interface{
+		Name:           name,
+		Kind:           internal.KindFunc,
+		ImportPath:     path(obj.Pkg()),
+		Doc:            im.docs[obj.Pos()],
+		TypeParameters: im.toTypeParameters(t),
+		Methods:        []internal.Method{m},
+		Position:       im.fset.Position(obj.Pos()),
+	}
+	r.Type = im.typeString(t, r.TypeParameters)
+
+	return r
+}
+
+// typeString returns the type as written in the generated code, with the names of its type parameters if it is generic.
+func (im *Importer) typeString(t types.Type, tps []internal.TypeParameter) string {
+	s := types.TypeString(t, im.qualifier.Qualify)
+	if len(tps) == 0 {
+		return s
+	}
+
+	names := make([]string, len(tps))
+	for i, tp := range tps {
+		names[i] = tp.Name
+	}
+
+	s, _, _ = strings.Cut(s, "[")
+
+	return s + "[" + strings.Join(names, ", ") + "]"
+}
+
+// isFunc reports whether the type is a named function type.
+func isFunc(t types.Type) bool {
+	_, named := types.Unalias(t).(*types.Named)
+	_, ok := t.Underlying().(*types.Signature)
+
+	return named && ok
+}
+
+// isStruct reports whether the type is a named struct type.
+func isStruct(t types.Type) bool {
+	_, named := types.Unalias(t).(*types.Named)
+	_, ok := t.Underlying().(*types.Struct)
+
+	return named && ok
+}
+
+// origin returns the name of the embedded interface the method comes from,
+// or an empty string if the method is declared by the interface itself.
+func origin(iface *types.Interface, f *types.Func, embeds []types.Type, qf types.Qualifier) string {
+	for i := 0; i < iface.NumExplicitMethods(); i++ {
+		if iface.ExplicitMethod(i).Name() == f.Name() {
+			return ""
+		}
+	}
+
+	for _, e := range embeds {
+		if obj, _, _ := types.LookupFieldOrMethod(e, false, f.Pkg(), f.Name()); obj != nil {
+			return types.TypeString(e, qf)
+		}
+	}
+
+	return ""
+}
+
+func path(pkg *types.Package) string {
+	if pkg == nil {
+		return ""
+	}
+
+	return pkg.Path()
+}
+
+func (im *Importer) toTypeParameters(t types.Type) []internal.TypeParameter {
+	g, ok := t.(interface {
+		TypeParams() *types.TypeParamList
+		TypeArgs() *types.TypeList
+	})
+	if !ok || g.TypeArgs().Len() > 0 {
+		return nil
+	}
+
+	tps := g.TypeParams()
+
+	var r []internal.TypeParameter
+	for i := 0; i < tps.Len(); i++ {
+		tp := tps.At(i)
+		r = append(r, internal.TypeParameter{
+			Name:       tp.Obj().Name(),
+			Constraint: types.TypeString(tp.Constraint(), im.qualifier.Qualify),
+		})
 	}
 
 	return r
 }
 
 func (im *Importer) toMethod(f *types.Func) internal.Method {
-	s := f.Type().(*types.Signature)
+	r := im.toSignature(f.Name(), f.Type().(*types.Signature))
+	r.Doc = im.docs[f.Pos()]
+	r.Position = im.fset.Position(f.Pos())
 
+	return r
+}
+
+func (im *Importer) toSignature(name string, s *types.Signature) internal.Method {
 	r := internal.Method{
-		Name:     f.Name(),
+		Name:     name,
 		Variadic: s.Variadic(),
 	}
 
@@ -120,8 +655,56 @@ func (im *Importer) toMethod(f *types.Func) internal.Method {
 }
 
 func (im *Importer) toVariable(v *types.Var) internal.Variable {
+	i := im.toTypeInfo(v.Type())
+
 	return internal.Variable{
-		Name: v.Name(),
-		Type: types.TypeString(v.Type(), im.qualifier.Qualify),
+		Name:     v.Name(),
+		Type:     i.Type,
+		TypeInfo: i,
+		Position: im.fset.Position(v.Pos()),
 	}
 }
+
+// docs returns the doc comments of type declarations and methods by the position of their names.
+func docs(files []*ast.File) map[token.Pos]string {
+	r := make(map[token.Pos]string)
+
+	add := func(id *ast.Ident, doc *ast.CommentGroup) {
+		if doc != nil {
+			r[id.Pos()] = strings.TrimSpace(doc.Text())
+		}
+	}
+
+	for _, f := range files {
+		for _, d := range f.Decls {
+			if fd, ok := d.(*ast.FuncDecl); ok && fd.Recv != nil {
+				add(fd.Name, fd.Doc)
+			}
+
+			decl, ok := d.(*ast.GenDecl)
+			if !ok || decl.Tok != token.TYPE {
+				continue
+			}
+
+			for _, s := range decl.Specs {
+				spec := s.(*ast.TypeSpec)
+
+				if spec.Doc == nil && !decl.Lparen.IsValid() {
+					add(spec.Name, decl.Doc)
+				} else {
+					add(spec.Name, spec.Doc)
+				}
+
+				if iface, ok := spec.Type.(*ast.InterfaceType); ok {
+					for _, m := range iface.Methods.List {
+						if len(m.Names) > 0 {
+							add(m.Names[0], m.Doc)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return r
+}
@@ -1,7 +1,10 @@
 package importer
 
 import (
+	"go/token"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -18,9 +21,66 @@ func testImporter_Parse(t *testing.T, exporter packagestest.Exporter) {
 		Files: map[string]interface{}{
 			"a/a.go": `package a; import "io"; import "golang.org/fake/b"; import bv2 "golang.org/fake/b/v2"; ` +
 				`type I1 interface { io.Writer; F(b b.B, b2 bv2.B, args ...string) error }; type I2 interface {}`,
-			"b/b.go":    `package b; type B string`,
+			"b/b.go":    `package b; type B string; type ID = string`,
 			"b/v2/b.go": `package b; type B string`,
 			"c/c.go":    `package c; import "io"; import "golang.org/fake/b"; type I interface { F(b b.B, w io.Writer) }`,
+			"d/d.go": `package d; import "golang.org/fake/b"; ` +
+				`type Repository[T any, K ~int | b.B] interface { Get(id K) (T, error); Put(items ...T) }`,
+			"d/lock.go":               `package d; import "golang.org/fake/d/internal/lock"; var _ lock.Mutex`,
+			"d/internal/lock/lock.go": `package lock; type Mutex int`,
+			"e/e.go": `package e
+
+import "io"
+
+// Base is a base interface.
+type Base interface {
+	io.Reader
+	// Close closes.
+	Close() error
+}
+
+type (
+	// I is an interface.
+	I interface {
+		Base
+		io.Writer
+		Close() error
+		// G does
+		// nothing.
+		G()
+	}
+)`,
+			"f/f.go": `package f
+
+import "io"
+
+// Client is a client.
+type Client struct{ io.Closer }
+
+// Get gets.
+func (c Client) Get(id string) (string, error) { return "", nil }
+
+func (c *Client) Set(id string) {}
+
+func (c *Client) reset() {}`,
+			"g/g.go": `package g; import "time"; ` +
+				`type RetryPolicy func(attempt int, err error) (time.Duration, bool); type Map[T any] func(T) T`,
+			"k/k.go": `package k; import "context"; import "io"; import "golang.org/fake/b"; ` +
+				`type Handler = func(ctx context.Context) error; type Closer = io.Closer; ` +
+				`type I interface { Closer; Handle(id b.ID, h Handler) Handler }`,
+			"m/m.go": `package m; import "golang.org/fake/b"; ` +
+				`type Good interface { F(b b.B) }; type Bad interface { G(x Missing) }; var x int = "s"`,
+			"n/internal/x/x.go": `package x; type T int`,
+			"n/n.go": `package n; import "golang.org/fake/n/internal/x"; ` +
+				`type I interface { F(t x.T) }; type J interface { G() []item }; type item int; type K[t any] interface { H(t) }; ` +
+				`type Sealed interface { Pub(); priv() }`,
+			"h/h.go": `package h`,
+			"u/u.go": `package u; type Service interface { Run() }; type store interface { get(id string) string }; var count int`,
+			"h/h_integration.go": `//go:build integration
+
+package h
+
+type I interface{ F() }`,
 		}}})
 	defer e.Cleanup()
 
@@ -36,7 +96,9 @@ func testImporter_Parse(t *testing.T, exporter packagestest.Exporter) {
 		Imports: []internal.Import{{Name: "b", Path: "golang.org/fake/b"}, {Name: "b", Alias: "b2", Path: "golang.org/fake/b/v2"}},
 		Interfaces: []internal.Interface{
 			{
-				Name: "I1",
+				Name:   "I1",
+				Kind:   internal.KindInterface,
+				Embeds: []internal.Embed{{Name: "io.Writer", Path: "io"}},
 				Methods: []internal.Method{
 					{
 						Name: "F",
@@ -52,10 +114,12 @@ func testImporter_Parse(t *testing.T, exporter packagestest.Exporter) {
 						Name:       "Write",
 						Parameters: []internal.Variable{{Name: "p", Type: "[]byte"}},
 						Results:    []internal.Variable{{Name: "n", Type: "int"}, {Name: "err", Type: "error"}},
+						Origin:     "io.Writer",
 					},
 				},
 			}, {
 				Name: "I2",
+				Kind: internal.KindInterface,
 			},
 		},
 	}
@@ -66,6 +130,7 @@ func testImporter_Parse(t *testing.T, exporter packagestest.Exporter) {
 		Interfaces: []internal.Interface{
 			{
 				Name: "I",
+				Kind: internal.KindInterface,
 				Methods: []internal.Method{
 					{
 						Name:       "F",
@@ -76,6 +141,223 @@ func testImporter_Parse(t *testing.T, exporter packagestest.Exporter) {
 		},
 	}
 
+	// In another package, the mock imports the source package for the interface type.
+	pkgCImport := internal.Package{
+		Name:       "c",
+		Imports:    []internal.Import{{Name: "c", Path: "golang.org/fake/c"}, {Name: "io", Path: "io"}},
+		Interfaces: pkgC.Interfaces,
+	}
+
+	pkgCNamed := internal.Package{
+		Name: "c",
+		Imports: []internal.Import{
+			{Name: "c", Path: "golang.org/fake/c"},
+			{Name: "b", Path: "golang.org/fake/b"},
+			{Name: "io", Path: "io"},
+		},
+		Interfaces: []internal.Interface{
+			{
+				Name: "I",
+				Kind: internal.KindInterface,
+				Methods: []internal.Method{
+					{
+						Name:       "F",
+						Parameters: []internal.Variable{{Name: "b", Type: "b.B"}, {Name: "w", Type: "io.Writer"}},
+					},
+				},
+			},
+		},
+	}
+
+	pkgD := internal.Package{
+		Name:    "d",
+		Imports: []internal.Import{{Name: "b", Path: "golang.org/fake/b"}},
+		Interfaces: []internal.Interface{
+			{
+				Name: "Repository",
+				Kind: internal.KindInterface,
+				TypeParameters: []internal.TypeParameter{
+					{Name: "T", Constraint: "any"},
+					{Name: "K", Constraint: "~int | b.B"},
+				},
+				Methods: []internal.Method{
+					{
+						Name:       "Get",
+						Parameters: []internal.Variable{{Name: "id", Type: "K"}},
+						Results:    []internal.Variable{{Type: "T"}, {Type: "error"}},
+					},
+					{
+						Name:       "Put",
+						Parameters: []internal.Variable{{Name: "items", Type: "[]T"}},
+						Variadic:   true,
+					},
+				},
+			},
+		},
+	}
+
+	pkgDInstance := internal.Package{
+		Name:    "d",
+		Imports: []internal.Import{{Name: "b", Path: "golang.org/fake/b"}},
+		Interfaces: []internal.Interface{
+			{
+				Name:          "BIntRepository",
+				Instantiation: "Repository[b.B, int]",
+				Kind:          internal.KindInterface,
+				Methods: []internal.Method{
+					{
+						Name:       "Get",
+						Parameters: []internal.Variable{{Name: "id", Type: "int"}},
+						Results:    []internal.Variable{{Type: "b.B"}, {Type: "error"}},
+					},
+					{
+						Name:       "Put",
+						Parameters: []internal.Variable{{Name: "items", Type: "[]b.B"}},
+						Variadic:   true,
+					},
+				},
+			},
+		},
+	}
+
+	pkgDPathInstance := internal.Package{
+		Name:    "d",
+		Imports: []internal.Import{{Name: "sync", Path: "sync"}},
+		Interfaces: []internal.Interface{
+			{
+				Name:          "MutexIntRepository",
+				Instantiation: "Repository[sync.Mutex, int]",
+				Kind:          internal.KindInterface,
+				Methods: []internal.Method{
+					{
+						Name:       "Get",
+						Parameters: []internal.Variable{{Name: "id", Type: "int"}},
+						Results:    []internal.Variable{{Type: "sync.Mutex"}, {Type: "error"}},
+					},
+					{
+						Name:       "Put",
+						Parameters: []internal.Variable{{Name: "items", Type: "[]sync.Mutex"}},
+						Variadic:   true,
+					},
+				},
+			},
+		},
+	}
+
+	read := internal.Method{
+		Name:       "Read",
+		Parameters: []internal.Variable{{Name: "p", Type: "[]byte"}},
+		Results:    []internal.Variable{{Name: "n", Type: "int"}, {Name: "err", Type: "error"}},
+	}
+	write := read
+	write.Name = "Write"
+
+	pkgE := internal.Package{
+		Name: "e",
+		Interfaces: []internal.Interface{
+			{
+				Name:   "Base",
+				Kind:   internal.KindInterface,
+				Doc:    "Base is a base interface.",
+				Embeds: []internal.Embed{{Name: "io.Reader", Path: "io"}},
+				Methods: []internal.Method{
+					{Name: "Close", Doc: "Close closes.", Results: []internal.Variable{{Type: "error"}}},
+					withOrigin(read, "io.Reader"),
+				},
+			}, {
+				Name:   "I",
+				Kind:   internal.KindInterface,
+				Doc:    "I is an interface.",
+				Embeds: []internal.Embed{{Name: "Base", Path: "golang.org/fake/e"}, {Name: "io.Writer", Path: "io"}},
+				Methods: []internal.Method{
+					{Name: "Close", Results: []internal.Variable{{Type: "error"}}},
+					{Name: "G", Doc: "G does\nnothing."},
+					withOrigin(read, "Base"),
+					withOrigin(write, "io.Writer"),
+				},
+			},
+		},
+	}
+
+	pkgF := internal.Package{
+		Name: "f",
+		Interfaces: []internal.Interface{
+			{
+				Name: "Client",
+				Kind: internal.KindStruct,
+				Doc:  "Client is a client.",
+				Methods: []internal.Method{
+					{Name: "Close", Results: []internal.Variable{{Type: "error"}}},
+					{
+						Name:       "Get",
+						Doc:        "Get gets.",
+						Parameters: []internal.Variable{{Name: "id", Type: "string"}},
+						Results:    []internal.Variable{{Type: "string"}, {Type: "error"}},
+					},
+					{Name: "Set", Parameters: []internal.Variable{{Name: "id", Type: "string"}}},
+				},
+			},
+		},
+	}
+
+	pkgG := internal.Package{
+		Name:    "g",
+		Imports: []internal.Import{{Name: "time", Path: "time"}},
+		Interfaces: []internal.Interface{
+			{
+				Name: "RetryPolicy",
+				Kind: internal.KindFunc,
+				Methods: []internal.Method{
+					{
+						Name:       "Func",
+						Parameters: []internal.Variable{{Name: "attempt", Type: "int"}, {Name: "err", Type: "error"}},
+						Results:    []internal.Variable{{Type: "time.Duration"}, {Type: "bool"}},
+					},
+				},
+			}, {
+				Name:           "Map",
+				Kind:           internal.KindFunc,
+				TypeParameters: []internal.TypeParameter{{Name: "T", Constraint: "any"}},
+				Methods: []internal.Method{
+					{
+						Name:       "Func",
+						Parameters: []internal.Variable{{Type: "T"}},
+						Results:    []internal.Variable{{Type: "T"}},
+					},
+				},
+			},
+		},
+	}
+
+	service := internal.Interface{Name: "Service", Kind: internal.KindInterface, Methods: []internal.Method{{Name: "Run"}}}
+	store := internal.Interface{
+		Name: "store",
+		Kind: internal.KindInterface,
+		Methods: []internal.Method{
+			{
+				Name:       "get",
+				Parameters: []internal.Variable{{Name: "id", Type: "string"}},
+				Results:    []internal.Variable{{Type: "string"}},
+			},
+		},
+	}
+
+	aliases := func(qualifier string) internal.Interface {
+		return internal.Interface{
+			Name:   "I",
+			Kind:   internal.KindInterface,
+			Embeds: []internal.Embed{{Name: "Closer", Path: "golang.org/fake/k"}},
+			Methods: []internal.Method{
+				{Name: "Close", Results: []internal.Variable{{Type: "error"}}, Origin: "Closer"},
+				{
+					Name:       "Handle",
+					Parameters: []internal.Variable{{Name: "id", Type: "b.ID"}, {Name: "h", Type: qualifier + "Handler"}},
+					Results:    []internal.Variable{{Type: qualifier + "Handler"}},
+				},
+			},
+		}
+	}
+
 	type args struct {
 		importPath string
 		interfaces []string
@@ -85,6 +367,12 @@ func testImporter_Parse(t *testing.T, exporter packagestest.Exporter) {
 		packageDir  string
 		packageName string
 		packagePath string
+		fromType    bool
+		unexported  bool
+		allowErrors bool
+		buildFlags  []string
+		include     []string
+		exclude     []string
 		args        args
 		want        internal.Package
 		assertion   assert.ErrorAssertionFunc
@@ -99,19 +387,27 @@ func testImporter_Parse(t *testing.T, exporter packagestest.Exporter) {
 			name:        "package import path qualifier",
 			packagePath: "golang.org/fake/b",
 			args:        args{"golang.org/fake/c", nil},
-			want:        pkgC,
+			want:        pkgCImport,
 			assertion:   assert.NoError,
 		}, {
 			name:        "package name qualifier",
 			packageName: "b",
+			packagePath: "golang.org/fake/c",
+			args:        args{"golang.org/fake/c", nil},
+			want:        pkgCNamed,
+			assertion:   assert.NoError,
+		}, {
+			name:        "source package name qualifier",
+			packageName: "c",
+			packagePath: "golang.org/fake/c",
 			args:        args{"golang.org/fake/c", nil},
-			want:        pkgC,
+			want:        internal.Package{Name: "c", Imports: pkgCNamed.Imports[1:], Interfaces: pkgCNamed.Interfaces},
 			assertion:   assert.NoError,
 		}, {
 			name:       "package directory qualifier",
 			packageDir: dir,
 			args:       args{"golang.org/fake/c", nil},
-			want:       pkgC,
+			want:       pkgCImport,
 			assertion:  assert.NoError,
 		}, {
 			name:        "no interface filtering",
@@ -119,6 +415,260 @@ func testImporter_Parse(t *testing.T, exporter packagestest.Exporter) {
 			args:        args{"golang.org/fake/a", nil},
 			want:        pkgA,
 			assertion:   assert.NoError,
+		}, {
+			name:        "generic interface",
+			packagePath: "golang.org/fake/d",
+			args:        args{"golang.org/fake/d", nil},
+			want:        pkgD,
+			assertion:   assert.NoError,
+		}, {
+			name:        "generic interface instance",
+			packagePath: "golang.org/fake/d",
+			args:        args{"golang.org/fake/d", []string{"Repository[b.B, int]"}},
+			want:        pkgDInstance,
+			assertion:   assert.NoError,
+		}, {
+			name:        "type argument given by import path",
+			packagePath: "golang.org/fake/d",
+			args:        args{"golang.org/fake/d", []string{"Repository[sync.Mutex, int]"}},
+			want:        pkgDPathInstance,
+			assertion:   assert.NoError,
+		}, {
+			name:      "type argument of a package not imported by the file",
+			args:      args{"golang.org/fake/d", []string{"Repository[lock.Mutex, int]"}},
+			assertion: assert.Error,
+		}, {
+			name:      "invalid type argument",
+			args:      args{"golang.org/fake/d", []string{"Repository[b.B, string]"}},
+			assertion: assert.Error,
+		}, {
+			name:      "undefined type argument",
+			args:      args{"golang.org/fake/d", []string{"Repository[c.C, int]"}},
+			assertion: assert.Error,
+		}, {
+			name:        "embedded interfaces",
+			packagePath: "golang.org/fake/e",
+			args:        args{"golang.org/fake/e", nil},
+			want:        pkgE,
+			assertion:   assert.NoError,
+		}, {
+			name:        "from type",
+			packagePath: "golang.org/fake/f",
+			fromType:    true,
+			args:        args{"golang.org/fake/f", []string{"Client"}},
+			want:        pkgF,
+			assertion:   assert.NoError,
+		}, {
+			name:      "from type disabled",
+			args:      args{"golang.org/fake/f", []string{"Client"}},
+			assertion: assert.Error,
+		}, {
+			name:      "from type not struct",
+			fromType:  true,
+			args:      args{"golang.org/fake/b", []string{"B"}},
+			assertion: assert.Error,
+		}, {
+			name:        "function types",
+			packagePath: "golang.org/fake/g",
+			args:        args{"golang.org/fake/g", []string{"RetryPolicy", "Map"}},
+			want:        pkgG,
+			assertion:   assert.NoError,
+		}, {
+			name:      "no function type filtering",
+			args:      args{"golang.org/fake/g", nil},
+			want:      internal.Package{Name: "g"},
+			assertion: assert.NoError,
+		}, {
+			name:        "include patterns",
+			packagePath: "golang.org/fake/a",
+			include:     []string{"1$", "^I2$"},
+			args:        args{"golang.org/fake/a", nil},
+			want:        pkgA,
+			assertion:   assert.NoError,
+		}, {
+			name:        "include patterns with names",
+			packagePath: "golang.org/fake/a",
+			include:     []string{"2"},
+			args:        args{"golang.org/fake/a", []string{"I1"}},
+			want:        pkgA,
+			assertion:   assert.NoError,
+		}, {
+			name:        "exclude patterns",
+			packagePath: "golang.org/fake/e",
+			exclude:     []string{"^I$"},
+			args:        args{"golang.org/fake/e", nil},
+			want:        internal.Package{Name: "e", Interfaces: pkgE.Interfaces[:1]},
+			assertion:   assert.NoError,
+		}, {
+			name:      "unmatched include pattern",
+			include:   []string{"I3"},
+			args:      args{"golang.org/fake/a", nil},
+			assertion: assert.Error,
+		}, {
+			name:      "unmatched exclude pattern",
+			exclude:   []string{"I3"},
+			args:      args{"golang.org/fake/a", nil},
+			assertion: assert.Error,
+		}, {
+			name:        "build tags",
+			packagePath: "golang.org/fake/h",
+			buildFlags:  []string{"-tags=integration"},
+			args:        args{"golang.org/fake/h", []string{"I"}},
+			want: internal.Package{Name: "h", Interfaces: []internal.Interface{
+				{Name: "I", Kind: internal.KindInterface, Methods: []internal.Method{{Name: "F"}}},
+			}},
+			assertion: assert.NoError,
+		}, {
+			name:      "missing build tags",
+			args:      args{"golang.org/fake/h", []string{"I"}},
+			assertion: assert.Error,
+		}, {
+			name:        "unexported skipped",
+			packagePath: "golang.org/fake/u",
+			args:        args{"golang.org/fake/u", nil},
+			want:        internal.Package{Name: "u", Interfaces: []internal.Interface{service}},
+			assertion:   assert.NoError,
+		}, {
+			name:        "unexported",
+			packagePath: "golang.org/fake/u",
+			unexported:  true,
+			args:        args{"golang.org/fake/u", nil},
+			want:        internal.Package{Name: "u", Interfaces: []internal.Interface{service, store}},
+			assertion:   assert.NoError,
+		}, {
+			name:        "unexported by name",
+			packageName: "u",
+			packagePath: "golang.org/fake/u",
+			unexported:  true,
+			args:        args{"golang.org/fake/u", []string{"store"}},
+			want:        internal.Package{Name: "u", Interfaces: []internal.Interface{store}},
+			assertion:   assert.NoError,
+		}, {
+			name:        "unexported disabled",
+			packagePath: "golang.org/fake/u",
+			args:        args{"golang.org/fake/u", []string{"store"}},
+			assertion:   assert.Error,
+		}, {
+			name:        "unexported in another package",
+			packageName: "mock",
+			unexported:  true,
+			args:        args{"golang.org/fake/u", []string{"store"}},
+			assertion:   assert.Error,
+		}, {
+			name:        "aliases",
+			packagePath: "golang.org/fake/k",
+			args:        args{"golang.org/fake/k", []string{"I"}},
+			want: internal.Package{
+				Name:       "k",
+				Imports:    []internal.Import{{Name: "b", Path: "golang.org/fake/b"}},
+				Interfaces: []internal.Interface{aliases("")},
+			},
+			assertion: assert.NoError,
+		}, {
+			name:        "aliases in another package",
+			packageName: "mock",
+			args:        args{"golang.org/fake/k", []string{"I"}},
+			want: internal.Package{
+				Name:       "k",
+				Imports:    []internal.Import{{Name: "k", Path: "golang.org/fake/k"}, {Name: "b", Path: "golang.org/fake/b"}},
+				Interfaces: []internal.Interface{aliases("k.")},
+			},
+			assertion: assert.NoError,
+		}, {
+			name: "package errors",
+			args: args{"golang.org/fake/m", []string{"Good"}},
+			assertion: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorContains(t, err, "m.go:1:") &&
+					assert.ErrorContains(t, err, "undefined: Missing") &&
+					assert.ErrorContains(t, err, `cannot use "s"`)
+			},
+		}, {
+			name:        "allow errors",
+			packagePath: "golang.org/fake/m",
+			allowErrors: true,
+			args:        args{"golang.org/fake/m", nil},
+			want: internal.Package{
+				Name:    "m",
+				Imports: []internal.Import{{Name: "b", Path: "golang.org/fake/b"}},
+				Interfaces: []internal.Interface{{
+					Name:    "Good",
+					Kind:    internal.KindInterface,
+					Methods: []internal.Method{{Name: "F", Parameters: []internal.Variable{{Name: "b", Type: "b.B"}}}},
+				}},
+			},
+			assertion: assert.NoError,
+		}, {
+			name:        "allow errors with invalid interface",
+			allowErrors: true,
+			args:        args{"golang.org/fake/m", []string{"Bad"}},
+			assertion:   assert.Error,
+		}, {
+			name:        "internal package in the tree",
+			packagePath: "golang.org/fake/n/mock",
+			args:        args{"golang.org/fake/n", []string{"I"}},
+			want: internal.Package{
+				Name:    "n",
+				Imports: []internal.Import{{Name: "n", Path: "golang.org/fake/n"}, {Name: "x", Path: "golang.org/fake/n/internal/x"}},
+				Interfaces: []internal.Interface{{
+					Name:    "I",
+					Kind:    internal.KindInterface,
+					Methods: []internal.Method{{Name: "F", Parameters: []internal.Variable{{Name: "t", Type: "x.T"}}}},
+				}},
+			},
+			assertion: assert.NoError,
+		}, {
+			name:        "internal package out of the tree",
+			packagePath: "golang.org/fake/mock",
+			args:        args{"golang.org/fake/n", []string{"I"}},
+			assertion: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorContains(t, err, "method F of I refers to golang.org/fake/n/internal/x.T, whose internal package")
+			},
+		}, {
+			name:        "unexported type in the package",
+			packagePath: "golang.org/fake/n",
+			args:        args{"golang.org/fake/n", []string{"J", "K"}},
+			want: internal.Package{
+				Name: "n",
+				Interfaces: []internal.Interface{{
+					Name:    "J",
+					Kind:    internal.KindInterface,
+					Methods: []internal.Method{{Name: "G", Results: []internal.Variable{{Type: "[]item"}}}},
+				}, {
+					Name:           "K",
+					Kind:           internal.KindInterface,
+					TypeParameters: []internal.TypeParameter{{Name: "t", Constraint: "any"}},
+					Methods:        []internal.Method{{Name: "H", Parameters: []internal.Variable{{Type: "t"}}}},
+				}},
+			},
+			assertion: assert.NoError,
+		}, {
+			name:        "unexported type out of the package",
+			packageName: "mock",
+			packagePath: "golang.org/fake/n",
+			args:        args{"golang.org/fake/n", []string{"J"}},
+			assertion: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorContains(t, err, "method G of J refers to golang.org/fake/n.item, which is unexported")
+			},
+		}, {
+			name:        "unexported method in the package",
+			packagePath: "golang.org/fake/n",
+			args:        args{"golang.org/fake/n", []string{"Sealed"}},
+			want: internal.Package{
+				Name: "n",
+				Interfaces: []internal.Interface{{
+					Name:    "Sealed",
+					Kind:    internal.KindInterface,
+					Methods: []internal.Method{{Name: "Pub"}, {Name: "priv"}},
+				}},
+			},
+			assertion: assert.NoError,
+		}, {
+			name:        "unexported method out of the package",
+			packagePath: "golang.org/fake/n/mock",
+			args:        args{"golang.org/fake/n", []string{"Sealed"}},
+			assertion: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorContains(t, err, "method priv of Sealed is unexported, so only code of golang.org/fake/n can implement it")
+			},
 		}, {
 			name:      "package not found",
 			args:      args{"golang.org/fake", nil},
@@ -144,21 +694,300 @@ func testImporter_Parse(t *testing.T, exporter packagestest.Exporter) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			b := QualifierBuilder{env: e.Config.Env}
+			b := (&QualifierBuilder{}).WithEnv(e.Config.Env).WithBuildFlags(tt.buildFlags)
 			qf, err := b.WithPackageDir(tt.packageDir).
 				WithPackageName(tt.packageName).
 				WithPackagePath(tt.packagePath).
 				Build()
 			require.NoError(t, err)
 
-			im := New(qf)
-			im.config.Dir = e.Config.Dir
-			im.config.Env = e.Config.Env
+			im := New(qf).
+				WithDir(e.Config.Dir).
+				WithEnv(e.Config.Env).
+				WithBuildFlags(tt.buildFlags).
+				WithFromType(tt.fromType).
+				WithUnexported(tt.unexported).
+				WithAllowErrors(tt.allowErrors).
+				WithPatterns(compile(tt.include), compile(tt.exclude))
 
 			got, err := im.Parse(tt.args.importPath, tt.args.interfaces...)
 
 			tt.assertion(t, err)
-			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want, stripped(got))
+		})
+	}
+}
+
+func TestImporter_Parse_positions(t *testing.T) {
+	packagestest.TestAll(t, testImporter_Parse_positions)
+}
+func testImporter_Parse_positions(t *testing.T, exporter packagestest.Exporter) {
+	const src = `package p
+
+// I is an interface.
+type I interface {
+	F(a int) (err error)
+}
+
+type S struct{}
+`
+	e := packagestest.Export(t, exporter, []packagestest.Module{{
+		Name:  "golang.org/fake",
+		Files: map[string]interface{}{"p/p.go": src},
+	}})
+	defer e.Cleanup()
+
+	fileName := e.File("golang.org/fake", "p/p.go")
+	position := func(s string) token.Position {
+		offset := strings.Index(src, s)
+		return token.Position{
+			Filename: fileName,
+			Offset:   offset,
+			Line:     strings.Count(src[:offset], "\n") + 1,
+			Column:   offset - strings.LastIndex(src[:offset], "\n"),
+		}
+	}
+
+	parse := func(interfaces ...string) (internal.Package, error) {
+		qf, err := (&QualifierBuilder{}).WithPackagePath("golang.org/fake/p").Build()
+		require.NoError(t, err)
+
+		im := New(qf).WithDir(e.Config.Dir).WithEnv(e.Config.Env)
+
+		return im.Parse("golang.org/fake/p", interfaces...)
+	}
+
+	got, err := parse("I")
+	require.NoError(t, err)
+	require.Len(t, got.Interfaces, 1)
+
+	iface := got.Interfaces[0]
+	assert.Equal(t, position("I interface"), iface.Position)
+	assert.Equal(t, position("F("), iface.Methods[0].Position)
+	assert.Equal(t, position("a int"), iface.Methods[0].Parameters[0].Position)
+	assert.Equal(t, position("err error"), iface.Methods[0].Results[0].Position)
+
+	_, err = parse("S")
+	assert.EqualError(t, err, position("S struct").String()+": S should be an interface, was golang.org/fake/p.S")
+
+	_, err = parse("X")
+	assert.EqualError(t, err, "golang.org/fake/p: interface X missing")
+}
+
+func TestImporter_Parse_types(t *testing.T) { packagestest.TestAll(t, testImporter_Parse_types) }
+func testImporter_Parse_types(t *testing.T, exporter packagestest.Exporter) {
+	e := packagestest.Export(t, exporter, []packagestest.Module{{
+		Name: "golang.org/fake",
+		Files: map[string]interface{}{
+			"p/p.go": `package p; type I interface { F() }; type R[T any, K comparable] interface { Get(id K) T }; ` +
+				`type S struct{}; func (S) F() {}; type Func func()`,
+		},
+	}})
+	defer e.Cleanup()
+
+	tests := []struct {
+		name        string
+		packageName string
+		interfaces  []string
+		want        []string
+	}{
+		{
+			name:        "local",
+			packageName: "p",
+			interfaces:  []string{"I", "R", "R[string, int]", "S", "Func"},
+			want:        []string{"I", "R[T, K]", "R[string, int]", "S", "Func"},
+		}, {
+			name:        "another package",
+			packageName: "mock",
+			interfaces:  []string{"I", "R", "R[string, int]", "S", "Func"},
+			want:        []string{"p.I", "p.R[T, K]", "p.R[string, int]", "p.S", "p.Func"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qf, err := (&QualifierBuilder{}).WithPackageName(tt.packageName).WithPackagePath("golang.org/fake/p").Build()
+			require.NoError(t, err)
+
+			im := New(qf).WithDir(e.Config.Dir).WithEnv(e.Config.Env).WithFromType(true)
+
+			got, err := im.Parse("golang.org/fake/p", tt.interfaces...)
+			require.NoError(t, err)
+			assert.Equal(t, "golang.org/fake/p", got.Path)
+
+			var types []string
+			for _, iface := range got.Interfaces {
+				assert.Equal(t, "golang.org/fake/p", iface.ImportPath)
+				types = append(types, iface.Type)
+			}
+
+			assert.Equal(t, tt.want, types)
+		})
+	}
+}
+
+func TestImporter_Parse_module(t *testing.T) {
+	e := packagestest.Export(t, packagestest.Modules, []packagestest.Module{{
+		Name: "golang.org/fake",
+		Files: map[string]interface{}{
+			"p/p.go":            `package p; import "golang.org/fake/p/internal/x"; type I interface { F(x.T) }`,
+			"p/internal/x/x.go": `package x; type T int`,
+		},
+	}})
+	defer e.Cleanup()
+
+	tests := []struct {
+		name        string
+		dir         string
+		interfaces  []string
+		want        []string
+		diagnostics string
+		warnings    string
+		assertion   assert.ErrorAssertionFunc
+	}{
+		{
+			name:        "new directory in the module",
+			dir:         filepath.Join(e.Config.Dir, "p", "mock"),
+			want:        []string{"p.I"},
+			diagnostics: "golang.org/fake/p.I: module golang.org/fake (main), directory " + filepath.Join(e.Config.Dir, "p") + "\n",
+			assertion:   assert.NoError,
+		}, {
+			name:      "new directory outside the internal tree",
+			dir:       filepath.Join(e.Config.Dir, "mock"),
+			warnings:  "warning: skipping golang.org/fake/p.I: ",
+			assertion: assert.NoError,
+		}, {
+			name:       "interface given by name outside the internal tree",
+			dir:        filepath.Join(e.Config.Dir, "mock"),
+			interfaces: []string{"I"},
+			assertion:  assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qf, err := (&QualifierBuilder{}).WithEnv(e.Config.Env).WithPackageDir(tt.dir).Build()
+			require.NoError(t, err)
+
+			var diagnostics, warnings strings.Builder
+			im := New(qf).WithDir(e.Config.Dir).WithEnv(e.Config.Env).WithDiagnostics(&diagnostics).WithWarnings(&warnings)
+
+			got, err := im.Parse("golang.org/fake/p", tt.interfaces...)
+			tt.assertion(t, err)
+			if err != nil {
+				return
+			}
+
+			var types []string
+			for _, iface := range got.Interfaces {
+				types = append(types, iface.Type)
+			}
+
+			assert.Equal(t, tt.want, types)
+			assert.Equal(t, tt.diagnostics, diagnostics.String())
+			if tt.warnings == "" {
+				assert.Empty(t, warnings.String())
+			} else {
+				assert.True(t, strings.HasPrefix(warnings.String(), tt.warnings), warnings.String())
+			}
 		})
 	}
 }
+
+func TestImporter_Load(t *testing.T) { packagestest.TestAll(t, testImporter_Load) }
+func testImporter_Load(t *testing.T, exporter packagestest.Exporter) {
+	e := packagestest.Export(t, exporter, []packagestest.Module{{
+		Name: "golang.org/fake",
+		Files: map[string]interface{}{
+			"a/a.go": `package a; type I1 interface { F() }; type I2 interface {}`,
+			"b/b.go": `package b; type B string`,
+			"c/c.go": `package c; type I interface { F() }`,
+		},
+	}})
+	defer e.Cleanup()
+
+	load := func(include, exclude []string) (map[string]internal.Package, error) {
+		im := New(nil).WithDir(e.Config.Dir).WithEnv(e.Config.Env).WithPatterns(compile(include), compile(exclude))
+
+		pkgs, err := im.Load("golang.org/fake/...")
+		if err != nil {
+			return nil, err
+		}
+
+		got := make(map[string]internal.Package)
+		for _, pkg := range pkgs {
+			qf, err := (&QualifierBuilder{}).WithPackagePath(pkg.PkgPath).Build()
+			require.NoError(t, err)
+
+			p, err := im.WithQualifier(qf).Parse(pkg.PkgPath)
+			require.NoError(t, err)
+
+			got[pkg.PkgPath] = p
+		}
+
+		return got, im.CheckPatterns()
+	}
+
+	names := func(p internal.Package) []string {
+		var r []string
+		for _, iface := range p.Interfaces {
+			r = append(r, iface.Name)
+		}
+
+		return r
+	}
+
+	got, err := load([]string{"^I"}, []string{"2$"})
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, []string{"I1"}, names(got["golang.org/fake/a"]))
+	assert.Empty(t, names(got["golang.org/fake/b"]))
+	assert.Equal(t, []string{"I"}, names(got["golang.org/fake/c"]))
+
+	_, err = load([]string{"I3"}, nil)
+	assert.Error(t, err)
+
+	_, err = load(nil, []string{"I3"})
+	assert.Error(t, err)
+}
+
+// stripped clears the source positions, which depend on the exporter, the type descriptors
+// and the source types, which are covered by dedicated tests.
+func stripped(p internal.Package) internal.Package {
+	p.Path = ""
+
+	clear := func(vs []internal.Variable) {
+		for i := range vs {
+			vs[i].TypeInfo = internal.TypeInfo{}
+			vs[i].Position = token.Position{}
+		}
+	}
+
+	for i := range p.Interfaces {
+		iface := &p.Interfaces[i]
+		iface.Position = token.Position{}
+		iface.ImportPath = ""
+		iface.Type = ""
+
+		for j := range iface.Methods {
+			m := &iface.Methods[j]
+			m.Position = token.Position{}
+			clear(m.Parameters)
+			clear(m.Results)
+		}
+	}
+
+	return p
+}
+
+func compile(patterns []string) []*regexp.Regexp {
+	var r []*regexp.Regexp
+	for _, p := range patterns {
+		r = append(r, regexp.MustCompile(p))
+	}
+
+	return r
+}
+
+func withOrigin(m internal.Method, origin string) internal.Method {
+	m.Origin = origin
+	return m
+}
@@ -0,0 +1,247 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// qualified matches the qualified identifiers of a type expression, whose qualifier is either a package name
+// or an import path such as golang.org/x/text/language.
+var qualified = regexp.MustCompile(`([\w.~-]+(?:/[\w.~-]+)*)\.(\w+)`)
+
+// instantiate resolves an instantiation such as Repo[user.User] of a generic type declared in pkg.
+// It returns the name of the mock and the instantiated type.
+func (im *Importer) instantiate(pkg *packages.Package, expr string) (string, types.Type, error) {
+	generic, _, _ := strings.Cut(expr, "[")
+	generic = strings.TrimSpace(generic)
+
+	if !token.IsIdentifier(generic) {
+		return "", nil, fmt.Errorf("invalid instantiation %s", expr)
+	}
+
+	obj := pkg.Types.Scope().Lookup(generic)
+	if obj == nil {
+		return "", nil, fmt.Errorf("%s: interface %s missing", pkg.Types.Path(), generic)
+	}
+
+	pos := pkg.Fset.Position(obj.Pos())
+
+	if _, ok := obj.(*types.TypeName); !ok || !types.IsInterface(obj.Type()) {
+		return "", nil, fmt.Errorf("%s: %s should be an interface, was %s", pos, generic, obj.Type())
+	}
+
+	// Type arguments are evaluated in a throwaway package rather than in the loaded one, whose scope must not change.
+	// They may refer to package-level declarations, to the packages imported by the file declaring the interface,
+	// and to other packages given by import path.
+	scope := types.NewPackage(pkg.Types.Path(), pkg.Types.Name())
+	for _, n := range pkg.Types.Scope().Names() {
+		scope.Scope().Insert(pkg.Types.Scope().Lookup(n))
+	}
+
+	insertImports(scope, pkg, obj.Pos())
+
+	src, err := im.qualify(scope, pkg, expr)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: invalid instantiation %s: %w", pos, expr, err)
+	}
+
+	x, err := parser.ParseExprFrom(pkg.Fset, "", src, 0)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid instantiation %s: %w", expr, err)
+	}
+
+	var indices []ast.Expr
+
+	switch x := x.(type) {
+	case *ast.IndexExpr:
+		indices = []ast.Expr{x.Index}
+	case *ast.IndexListExpr:
+		indices = x.Indices
+	default:
+		return "", nil, fmt.Errorf("invalid instantiation %s", expr)
+	}
+
+	targs := make([]types.Type, len(indices))
+
+	var names []string
+
+	for i, index := range indices {
+		info := &types.Info{Types: make(map[ast.Expr]types.TypeAndValue)}
+		if err := types.CheckExpr(pkg.Fset, scope, token.NoPos, index, info); err != nil {
+			var e types.Error
+			if errors.As(err, &e) {
+				err = errors.New(e.Msg)
+			}
+
+			return "", nil, fmt.Errorf("%s: invalid instantiation %s: %w", pos, expr, err)
+		}
+
+		tv := info.Types[index]
+		if !tv.IsType() {
+			return "", nil, fmt.Errorf("%s: invalid instantiation %s: %s is not a type", pos, expr, types.ExprString(index))
+		}
+
+		targs[i] = tv.Type
+		names = append(names, identifiers(index)...)
+	}
+
+	t, err := types.Instantiate(nil, obj.Type(), targs, true)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: invalid instantiation %s: %w", pos, expr, err)
+	}
+
+	return strings.Join(append(names, generic), ""), t, nil
+}
+
+// insertImports inserts into the scope of the throwaway package the packages imported by the file of pkg
+// containing pos, under the names the file imports them with.
+func insertImports(scope *types.Package, pkg *packages.Package, pos token.Pos) {
+	for _, f := range pkg.Syntax {
+		if pos < f.FileStart || pos > f.FileEnd {
+			continue
+		}
+
+		for _, spec := range f.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+
+			imported, ok := pkg.Imports[path]
+			if !ok || imported.Types == nil {
+				continue
+			}
+
+			name := imported.Types.Name()
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+
+			switch name {
+			case "_":
+			case ".":
+				for _, n := range imported.Types.Scope().Names() {
+					if o := imported.Types.Scope().Lookup(n); o.Exported() {
+						scope.Scope().Insert(o)
+					}
+				}
+			default:
+				scope.Scope().Insert(types.NewPkgName(spec.Pos(), scope, name, imported.Types))
+			}
+		}
+	}
+}
+
+// qualify returns the expression with the packages given by import path replaced by names inserted into the scope
+// of the throwaway package. Qualifiers that are names of packages imported by the file are kept.
+func (im *Importer) qualify(scope *types.Package, pkg *packages.Package, expr string) (string, error) {
+	var err error
+
+	r := qualified.ReplaceAllStringFunc(expr, func(s string) string {
+		m := qualified.FindStringSubmatch(s)
+		path, sel := m[1], m[2]
+
+		if _, ok := scope.Scope().Lookup(path).(*types.PkgName); ok {
+			return s
+		}
+
+		if path == pkg.Types.Path() {
+			return sel
+		}
+
+		p, e := im.importPath(pkg, path)
+		if e != nil {
+			err = errors.Join(err, e)
+			return s
+		}
+
+		if p == nil {
+			return s
+		}
+
+		name := p.Name()
+		for i := 2; scope.Scope().Lookup(name) != nil; i++ {
+			if n, ok := scope.Scope().Lookup(name).(*types.PkgName); ok && n.Imported() == p {
+				return name + "." + sel
+			}
+
+			name = fmt.Sprintf("%s%d", p.Name(), i)
+		}
+
+		scope.Scope().Insert(types.NewPkgName(token.NoPos, scope, name, p))
+
+		return name + "." + sel
+	})
+
+	return r, err
+}
+
+// importPath returns the package with the import path, among the dependencies of pkg or loaded otherwise.
+// It returns nil if there is no such package, so that the qualifier is reported as undefined.
+func (im *Importer) importPath(pkg *packages.Package, path string) (*types.Package, error) {
+	for _, p := range dependencies(pkg.Types) {
+		if p.Path() == path {
+			return p, nil
+		}
+	}
+
+	pkgs, err := packages.Load(im.config, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pkgs) != 1 || len(pkgs[0].Errors) > 0 || pkgs[0].Types == nil {
+		return nil, nil
+	}
+
+	return pkgs[0].Types, nil
+}
+
+// dependencies returns the packages imported by pkg, directly imported packages first.
+func dependencies(pkg *types.Package) []*types.Package {
+	seen := map[*types.Package]bool{pkg: true}
+	queue := []*types.Package{pkg}
+
+	var r []*types.Package
+	for len(queue) > 0 {
+		for _, p := range queue[0].Imports() {
+			if !seen[p] {
+				seen[p] = true
+				queue = append(queue, p)
+				r = append(r, p)
+			}
+		}
+
+		queue = queue[1:]
+	}
+
+	return r
+}
+
+// identifiers returns the capitalized identifiers of a type expression without package qualifiers.
+func identifiers(x ast.Expr) []string {
+	var r []string
+
+	ast.Inspect(x, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			r = append(r, strings.ToUpper(n.Sel.Name[:1])+n.Sel.Name[1:])
+			return false
+		case *ast.Ident:
+			r = append(r, strings.ToUpper(n.Name[:1])+n.Name[1:])
+		}
+
+		return true
+	})
+
+	return r
+}
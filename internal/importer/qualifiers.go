@@ -1,10 +1,15 @@
 package importer
 
 import (
+	"errors"
 	"fmt"
 	"go/types"
+	"io/fs"
+	"os"
 	"path/filepath"
+	"strings"
 
+	"golang.org/x/mod/modfile"
 	"golang.org/x/tools/go/packages"
 
 	"github.com/kokhanevych/gomockgen/internal"
@@ -13,11 +18,24 @@ import (
 // QualifierBuilder builds a qualifier.
 type QualifierBuilder struct {
 	env         []string
+	buildFlags  []string
 	packageDir  string
 	packageName string
 	packagePath string
 }
 
+// WithEnv sets the environment to use when loading the package of the package directory.
+func (b *QualifierBuilder) WithEnv(env []string) *QualifierBuilder {
+	b.env = env
+	return b
+}
+
+// WithBuildFlags sets the build flags to use when loading the package of the package directory.
+func (b *QualifierBuilder) WithBuildFlags(flags []string) *QualifierBuilder {
+	b.buildFlags = flags
+	return b
+}
+
 // WithPackageDir sets the package directory to use for the qualifier.
 func (b *QualifierBuilder) WithPackageDir(dir string) *QualifierBuilder {
 	b.packageDir = dir
@@ -50,6 +68,7 @@ func (b *QualifierBuilder) Build() (Qualifier, error) {
 		return &packageNameQualifier{
 			qualifier:   newQualifier(),
 			packageName: b.packageName,
+			packagePath: b.packagePath,
 		}, nil
 	}
 
@@ -59,19 +78,49 @@ func (b *QualifierBuilder) Build() (Qualifier, error) {
 	}, nil
 }
 
+// path returns the import path of the package in the directory. For a directory that does not hold a package yet,
+// it is the import path of the directory in the module of the closest go.mod file, empty without a module.
 func (b *QualifierBuilder) path(dir string) (string, error) {
 	dir, err := filepath.Abs(dir)
 	if err != nil {
 		return "", err
 	}
 
-	cfg := &packages.Config{Mode: packages.NeedName, Dir: dir, Env: b.env}
+	cfg := &packages.Config{Mode: packages.NeedName, Dir: dir, Env: b.env, BuildFlags: b.buildFlags}
 	pkgs, err := packages.Load(cfg, dir)
-	if err == nil && len(pkgs) == 1 {
+	if err == nil && len(pkgs) == 1 && len(pkgs[0].Errors) == 0 {
 		return pkgs[0].PkgPath, nil
 	}
 
-	return "", nil
+	return modulePath(dir)
+}
+
+// modulePath returns the import path of the directory in the module of the closest go.mod file,
+// empty if there is none.
+func modulePath(dir string) (string, error) {
+	for d := dir; ; d = filepath.Dir(d) {
+		data, err := os.ReadFile(filepath.Join(d, "go.mod"))
+		if err == nil {
+			rel, err := filepath.Rel(d, dir)
+			if err != nil {
+				return "", err
+			}
+
+			if rel == "." {
+				return modfile.ModulePath(data), nil
+			}
+
+			return modfile.ModulePath(data) + "/" + filepath.ToSlash(rel), nil
+		}
+
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+
+		if filepath.Dir(d) == d {
+			return "", nil
+		}
+	}
 }
 
 type qualifier struct {
@@ -130,24 +179,62 @@ type packagePathQualifier struct {
 
 // Qualify controls how named package-level objects are printed.
 func (q *packagePathQualifier) Qualify(pkg *types.Package) string {
-	if pkg.Path() == q.packagePath {
+	if q.Local(pkg) {
 		return ""
 	}
 
 	return q.qualify(pkg)
 }
 
-// packageNameQualifier represents a qualifier using the package name.
+// Local reports whether the package is the package of the generated code.
+func (q *packagePathQualifier) Local(pkg *types.Package) bool {
+	return pkg.Path() == q.packagePath
+}
+
+// Importable reports whether the package path can be imported by the generated code.
+func (q *packagePathQualifier) Importable(path string) bool {
+	return q.packagePath == "" || importable(q.packagePath, path)
+}
+
+// packageNameQualifier represents a qualifier using the package name of the generated code
+// and the package path of the mocked interfaces, which are only local when their package has this name.
 type packageNameQualifier struct {
 	qualifier
 	packageName string
+	packagePath string
 }
 
 // Qualify controls how named package-level objects are printed.
 func (q *packageNameQualifier) Qualify(pkg *types.Package) string {
-	if pkg.Name() == q.packageName {
+	if q.Local(pkg) {
 		return ""
 	}
 
 	return q.qualify(pkg)
 }
+
+// Local reports whether the package is the package of the generated code.
+func (q *packageNameQualifier) Local(pkg *types.Package) bool {
+	return pkg.Path() == q.packagePath && pkg.Name() == q.packageName
+}
+
+// Importable reports whether the package path can be imported by the generated code,
+// which is always assumed since the location of the generated code is unknown.
+func (q *packageNameQualifier) Importable(string) bool {
+	return true
+}
+
+// importable reports whether the package path can be imported from the package path according to the rule
+// of internal packages: a path with an internal element is only importable from the tree rooted at its parent.
+func importable(from, path string) bool {
+	elems := strings.Split(path, "/")
+
+	for i := len(elems) - 1; i >= 0; i-- {
+		if elems[i] == "internal" {
+			parent := strings.Join(elems[:i], "/")
+			return parent != "" && (from == parent || strings.HasPrefix(from, parent+"/"))
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,130 @@
+package importer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strconv"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// LoadFile loads the package made of the given Go source file only, so that its interfaces can be parsed
+// with the import path of the returned package. The file does not need to belong to a buildable package:
+// type errors are kept in the package, and the interfaces depending on invalid types are skipped with a warning,
+// or fail if they are given by name.
+func (im *Importer) LoadFile(fileName string) (*packages.Package, error) {
+	fileName, err := filepath.Abs(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, fileName, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(fileName)
+
+	imports, err := im.loadImports(fset, dir, file)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &packages.Package{
+		Name:            file.Name.Name,
+		PkgPath:         im.pkgPath(dir, file.Name.Name),
+		Dir:             dir,
+		GoFiles:         []string{fileName},
+		CompiledGoFiles: []string{fileName},
+		Fset:            fset,
+		Syntax:          []*ast.File{file},
+		Imports:         imports,
+	}
+	pkg.ID = pkg.PkgPath
+
+	conf := types.Config{
+		Importer: importerFunc(func(path string) (*types.Package, error) {
+			if p, ok := imports[path]; ok && p.Types != nil {
+				return p.Types, nil
+			}
+
+			return nil, fmt.Errorf("package %s not found", path)
+		}),
+		Error: func(err error) {
+			if err, ok := err.(types.Error); ok {
+				pkg.TypeErrors = append(pkg.TypeErrors, err)
+			}
+		},
+	}
+
+	pkg.TypesInfo = &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Implicits:  make(map[ast.Node]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		Scopes:     make(map[ast.Node]*types.Scope),
+	}
+	pkg.Types, _ = conf.Check(pkg.PkgPath, fset, pkg.Syntax, pkg.TypesInfo)
+
+	im.packages[pkg.PkgPath] = pkg
+	im.files[pkg.PkgPath] = true
+
+	return pkg, nil
+}
+
+// loadImports loads the packages imported by the file, with positions in the file set of the file.
+func (im *Importer) loadImports(fset *token.FileSet, dir string, file *ast.File) (map[string]*packages.Package, error) {
+	var paths []string
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, path)
+	}
+
+	imports := make(map[string]*packages.Package)
+	if len(paths) == 0 {
+		return imports, nil
+	}
+
+	cfg := *im.config
+	cfg.Mode = packages.NeedName | packages.NeedTypes | packages.NeedImports
+	cfg.Dir = dir
+	cfg.Fset = fset
+
+	pkgs, err := packages.Load(&cfg, paths...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range pkgs {
+		imports[p.PkgPath] = p
+	}
+
+	return imports, nil
+}
+
+// pkgPath returns the import path of the package in the directory, or the package name if it has none.
+func (im *Importer) pkgPath(dir, name string) string {
+	cfg := &packages.Config{Mode: packages.NeedName, Dir: dir, Env: im.config.Env, BuildFlags: im.config.BuildFlags}
+
+	pkgs, err := packages.Load(cfg, ".")
+	if err == nil && len(pkgs) == 1 && pkgs[0].PkgPath != "" && pkgs[0].Name == name {
+		return pkgs[0].PkgPath
+	}
+
+	return name
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
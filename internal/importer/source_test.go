@@ -0,0 +1,75 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages/packagestest"
+
+	"github.com/kokhanevych/gomockgen/internal"
+)
+
+func TestImporter_LoadFile(t *testing.T) { packagestest.TestAll(t, testImporter_LoadFile) }
+func testImporter_LoadFile(t *testing.T, exporter packagestest.Exporter) {
+	e := packagestest.Export(t, exporter, []packagestest.Module{{
+		Name: "golang.org/fake",
+		Files: map[string]interface{}{
+			"b/b.go": `package b; type B string`,
+			"s/s.go": `package s; import "golang.org/fake/b"; ` +
+				`type I interface { F(b b.B) T }; type T struct{}; type Broken interface { G(m Missing) }`,
+			"s/other.go": `package s; var x int = "broken"`,
+		},
+	}})
+	defer e.Cleanup()
+
+	var warnings strings.Builder
+
+	parse := func(interfaces ...string) (internal.Package, error) {
+		warnings.Reset()
+		im := New(nil).WithDir(e.Config.Dir).WithEnv(e.Config.Env).WithWarnings(&warnings)
+
+		pkg, err := im.LoadFile(e.File("golang.org/fake", "s/s.go"))
+		require.NoError(t, err)
+		require.Equal(t, "golang.org/fake/s", pkg.PkgPath)
+
+		qf, err := (&QualifierBuilder{}).WithPackagePath(pkg.PkgPath).Build()
+		require.NoError(t, err)
+
+		return im.WithQualifier(qf).Parse(pkg.PkgPath, interfaces...)
+	}
+
+	pkgI := internal.Package{
+		Name:    "s",
+		Imports: []internal.Import{{Name: "b", Path: "golang.org/fake/b"}},
+		Interfaces: []internal.Interface{
+			{
+				Name: "I",
+				Kind: internal.KindInterface,
+				Methods: []internal.Method{
+					{
+						Name:       "F",
+						Parameters: []internal.Variable{{Name: "b", Type: "b.B"}},
+						Results:    []internal.Variable{{Type: "T"}},
+					},
+				},
+			},
+		},
+	}
+
+	got, err := parse("I")
+	require.NoError(t, err)
+	assert.Equal(t, pkgI, stripped(got))
+	assert.Empty(t, warnings.String())
+
+	got, err = parse()
+	require.NoError(t, err)
+	assert.Equal(t, pkgI, stripped(got))
+	assert.Contains(t, warnings.String(), "warning: skipping golang.org/fake/s.Broken: ")
+	assert.Contains(t, warnings.String(), "undefined: Missing")
+
+	_, err = parse("Broken")
+	assert.ErrorContains(t, err, "Broken has invalid types")
+	assert.ErrorContains(t, err, "undefined: Missing")
+}
@@ -0,0 +1,203 @@
+package importer
+
+import (
+	"go/types"
+
+	"github.com/kokhanevych/gomockgen/internal"
+)
+
+func (im *Importer) toTypeInfo(t types.Type) internal.TypeInfo {
+	r := internal.TypeInfo{
+		Type:       types.TypeString(t, im.qualifier.Qualify),
+		Comparable: types.Comparable(t),
+	}
+
+	switch t := t.(type) {
+	case *types.Basic:
+		r.Kind = internal.KindBasic
+	case *types.Pointer:
+		r.Kind = internal.KindPointer
+		r.Elem = im.toElem(t.Elem())
+	case *types.Array:
+		r.Kind = internal.KindArray
+		r.Elem = im.toElem(t.Elem())
+	case *types.Slice:
+		r.Kind = internal.KindSlice
+		r.Elem = im.toElem(t.Elem())
+	case *types.Map:
+		r.Kind = internal.KindMap
+		r.Key = im.toElem(t.Key())
+		r.Elem = im.toElem(t.Elem())
+	case *types.Chan:
+		r.Kind = internal.KindChan
+		r.Elem = im.toElem(t.Elem())
+	case *types.Signature:
+		r.Kind = internal.KindFunc
+	case *types.Interface:
+		r.Kind = internal.KindInterface
+	case *types.Struct:
+		r.Kind = internal.KindStruct
+	case *types.TypeParam:
+		r.Kind = internal.KindTypeParam
+	case *types.Alias:
+		if t.Obj().Pkg() == nil {
+			// Predeclared aliases such as any are described by the type they denote.
+			i := im.toTypeInfo(types.Unalias(t))
+			i.Type = r.Type
+			return i
+		}
+
+		r.Kind = internal.KindNamed
+	case *types.Named:
+		r.Kind = internal.KindNamed
+	}
+
+	r.Nillable, r.Zero = zero(t, r.Type)
+
+	return r
+}
+
+func (im *Importer) toElem(t types.Type) *internal.TypeInfo {
+	r := im.toTypeInfo(t)
+	return &r
+}
+
+// zero reports whether nil is the zero value of the type and returns the zero value expression of the type.
+func zero(t types.Type, typeString string) (bool, string) {
+	if _, ok := t.(*types.TypeParam); ok {
+		return false, "*new(" + typeString + ")"
+	}
+
+	_, named := types.Unalias(t).(*types.Named)
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		var v string
+
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			v = "false"
+		case u.Info()&types.IsNumeric != 0:
+			v = "0"
+		case u.Info()&types.IsString != 0:
+			v = `""`
+		default:
+			return true, "nil"
+		}
+
+		if named {
+			return false, typeString + "(" + v + ")"
+		}
+
+		return false, v
+	case *types.Array, *types.Struct:
+		return false, typeString + "{}"
+	default:
+		return true, "nil"
+	}
+}
+
+// methods returns the methods to mock of the named type: the methods of an interface, the exported methods
+// of the method set of a pointer to a struct, or a method named Func with the signature of a function type.
+func methods(t types.Type) []*types.Func {
+	var r []*types.Func
+
+	switch u := t.Underlying().(type) {
+	case *types.Interface:
+		for i := range u.NumMethods() {
+			r = append(r, u.Method(i))
+		}
+	case *types.Signature:
+		obj := t.(interface{ Obj() *types.TypeName }).Obj()
+		r = append(r, types.NewFunc(obj.Pos(), obj.Pkg(), "Func", u))
+	case *types.Struct:
+		ms := types.NewMethodSet(types.NewPointer(t))
+		for i := range ms.Len() {
+			if m := ms.At(i).Obj().(*types.Func); m.Exported() {
+				r = append(r, m)
+			}
+		}
+	}
+
+	return r
+}
+
+// valid reports whether the methods to mock of the named type only have valid types.
+func valid(t types.Type) bool {
+	for _, m := range methods(t) {
+		if invalid(m.Type()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// invalid reports whether the type refers to an invalid type.
+func invalid(t types.Type) bool {
+	return !walk(t, func(t types.Type) bool {
+		b, ok := types.Unalias(t).(*types.Basic)
+		return !ok || b.Kind() != types.Invalid
+	})
+}
+
+// walk calls f for the type and the types it is made of, except the underlying types of named types and aliases,
+// as long as f returns true. It reports whether all the calls returned true.
+func walk(t types.Type, f func(types.Type) bool) bool {
+	if !f(t) {
+		return false
+	}
+
+	var elems []types.Type
+
+	switch t := t.(type) {
+	case *types.Pointer:
+		elems = append(elems, t.Elem())
+	case *types.Array:
+		elems = append(elems, t.Elem())
+	case *types.Slice:
+		elems = append(elems, t.Elem())
+	case *types.Map:
+		elems = append(elems, t.Key(), t.Elem())
+	case *types.Chan:
+		elems = append(elems, t.Elem())
+	case *types.Tuple:
+		for i := range t.Len() {
+			elems = append(elems, t.At(i).Type())
+		}
+	case *types.Signature:
+		elems = append(elems, t.Params(), t.Results())
+	case *types.Interface:
+		for i := range t.NumEmbeddeds() {
+			elems = append(elems, t.EmbeddedType(i))
+		}
+
+		for i := range t.NumExplicitMethods() {
+			elems = append(elems, t.ExplicitMethod(i).Type())
+		}
+	case *types.Struct:
+		for i := range t.NumFields() {
+			elems = append(elems, t.Field(i).Type())
+		}
+	case *types.Union:
+		for i := range t.Len() {
+			elems = append(elems, t.Term(i).Type())
+		}
+	case *types.Alias:
+		for i := range t.TypeArgs().Len() {
+			elems = append(elems, t.TypeArgs().At(i))
+		}
+	case *types.Named:
+		for i := range t.TypeArgs().Len() {
+			elems = append(elems, t.TypeArgs().At(i))
+		}
+	}
+
+	for _, e := range elems {
+		if !walk(e, f) {
+			return false
+		}
+	}
+
+	return true
+}
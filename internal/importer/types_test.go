@@ -0,0 +1,123 @@
+package importer
+
+import (
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kokhanevych/gomockgen/internal"
+)
+
+func TestImporter_toTypeInfo(t *testing.T) {
+	a := types.NewPackage("golang.org/fake/a", "a")
+	b := types.NewPackage("golang.org/fake/b", "b")
+
+	named := func(pkg *types.Package, name string, underlying types.Type) *types.Named {
+		return types.NewNamed(types.NewTypeName(token.NoPos, pkg, name, nil), underlying, nil)
+	}
+
+	intType := types.Typ[types.Int]
+	stringType := types.Typ[types.String]
+	duration := named(b, "Duration", types.Typ[types.Int64])
+	point := named(b, "Point", types.NewStruct(nil, nil))
+	reader := named(a, "Reader", types.NewInterfaceType(nil, nil).Complete())
+	id := types.NewAlias(types.NewTypeName(token.NoPos, b, "ID", nil), stringType)
+	tp := types.NewTypeParam(types.NewTypeName(token.NoPos, a, "T", nil), types.Universe.Lookup("any").Type())
+
+	intInfo := internal.TypeInfo{Kind: internal.KindBasic, Type: "int", Comparable: true, Zero: "0"}
+	stringInfo := internal.TypeInfo{Kind: internal.KindBasic, Type: "string", Comparable: true, Zero: `""`}
+
+	tests := []struct {
+		name string
+		t    types.Type
+		want internal.TypeInfo
+	}{
+		{
+			name: "bool",
+			t:    types.Typ[types.Bool],
+			want: internal.TypeInfo{Kind: internal.KindBasic, Type: "bool", Comparable: true, Zero: "false"},
+		}, {
+			name: "int",
+			t:    intType,
+			want: intInfo,
+		}, {
+			name: "string",
+			t:    stringType,
+			want: stringInfo,
+		}, {
+			name: "unsafe pointer",
+			t:    types.Typ[types.UnsafePointer],
+			want: internal.TypeInfo{Kind: internal.KindBasic, Type: "unsafe.Pointer", Nillable: true, Comparable: true, Zero: "nil"},
+		}, {
+			name: "pointer",
+			t:    types.NewPointer(intType),
+			want: internal.TypeInfo{Kind: internal.KindPointer, Type: "*int", Elem: &intInfo, Nillable: true, Comparable: true, Zero: "nil"},
+		}, {
+			name: "array",
+			t:    types.NewArray(intType, 3),
+			want: internal.TypeInfo{Kind: internal.KindArray, Type: "[3]int", Elem: &intInfo, Comparable: true, Zero: "[3]int{}"},
+		}, {
+			name: "slice",
+			t:    types.NewSlice(intType),
+			want: internal.TypeInfo{Kind: internal.KindSlice, Type: "[]int", Elem: &intInfo, Nillable: true, Zero: "nil"},
+		}, {
+			name: "map",
+			t:    types.NewMap(stringType, intType),
+			want: internal.TypeInfo{Kind: internal.KindMap, Type: "map[string]int", Key: &stringInfo, Elem: &intInfo, Nillable: true, Zero: "nil"},
+		}, {
+			name: "chan",
+			t:    types.NewChan(types.RecvOnly, intType),
+			want: internal.TypeInfo{Kind: internal.KindChan, Type: "<-chan int", Elem: &intInfo, Nillable: true, Comparable: true, Zero: "nil"},
+		}, {
+			name: "func",
+			t:    types.NewSignatureType(nil, nil, nil, nil, nil, false),
+			want: internal.TypeInfo{Kind: internal.KindFunc, Type: "func()", Nillable: true, Zero: "nil"},
+		}, {
+			name: "any",
+			t:    types.Universe.Lookup("any").Type(),
+			want: internal.TypeInfo{Kind: internal.KindInterface, Type: "any", Nillable: true, Comparable: true, Zero: "nil"},
+		}, {
+			name: "struct",
+			t:    types.NewStruct(nil, nil),
+			want: internal.TypeInfo{Kind: internal.KindStruct, Type: "struct{}", Comparable: true, Zero: "struct{}{}"},
+		}, {
+			name: "type parameter",
+			t:    tp,
+			want: internal.TypeInfo{Kind: internal.KindTypeParam, Type: "T", Zero: "*new(T)"},
+		}, {
+			name: "named basic",
+			t:    duration,
+			want: internal.TypeInfo{Kind: internal.KindNamed, Type: "b.Duration", Comparable: true, Zero: "b.Duration(0)"},
+		}, {
+			name: "named struct",
+			t:    types.NewPointer(point),
+			want: internal.TypeInfo{
+				Kind:       internal.KindPointer,
+				Type:       "*b.Point",
+				Elem:       &internal.TypeInfo{Kind: internal.KindNamed, Type: "b.Point", Comparable: true, Zero: "b.Point{}"},
+				Nillable:   true,
+				Comparable: true,
+				Zero:       "nil",
+			},
+		}, {
+			name: "named interface",
+			t:    reader,
+			want: internal.TypeInfo{Kind: internal.KindNamed, Type: "Reader", Nillable: true, Comparable: true, Zero: "nil"},
+		}, {
+			name: "alias",
+			t:    id,
+			want: internal.TypeInfo{Kind: internal.KindNamed, Type: "b.ID", Comparable: true, Zero: `""`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qf, err := (&QualifierBuilder{}).WithPackagePath(a.Path()).Build()
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, New(qf).toTypeInfo(tt.t))
+		})
+	}
+}
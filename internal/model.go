@@ -1,5 +1,7 @@
 package internal
 
+import "go/token"
+
 // Import represents an imported Go package.
 type Import struct {
 	Name  string
@@ -7,29 +9,91 @@ type Import struct {
 	Path  string
 }
 
+// Kind represents the kind of a Go type.
+type Kind string
+
+// Kinds of Go types.
+const (
+	KindBasic     Kind = "basic"
+	KindPointer   Kind = "pointer"
+	KindArray     Kind = "array"
+	KindSlice     Kind = "slice"
+	KindMap       Kind = "map"
+	KindChan      Kind = "chan"
+	KindFunc      Kind = "func"
+	KindInterface Kind = "interface"
+	KindStruct    Kind = "struct"
+	KindNamed     Kind = "named"
+	KindTypeParam Kind = "typeparam"
+)
+
+// TypeInfo describes a Go type.
+// Elem is set for pointer, array, slice, map and chan types, Key is set for map types.
+// Zero is an expression of the zero value of the type.
+type TypeInfo struct {
+	Kind       Kind
+	Type       string
+	Elem       *TypeInfo
+	Key        *TypeInfo
+	Nillable   bool
+	Comparable bool
+	Zero       string
+}
+
 // Variable represents a Go variable.
 type Variable struct {
-	Name string
-	Type string
+	Name     string
+	Type     string
+	TypeInfo TypeInfo
+	Position token.Position
+}
+
+// TypeParameter represents a Go type parameter.
+type TypeParameter struct {
+	Name       string
+	Constraint string
 }
 
 // Method represents a Go interface method.
 type Method struct {
 	Name       string
+	Doc        string
 	Parameters []Variable
 	Variadic   bool
 	Results    []Variable
+	// Origin is the name of the embedded interface the method comes from, empty if declared directly.
+	Origin   string
+	Position token.Position
+}
+
+// Embed represents a Go interface embedded in another interface.
+type Embed struct {
+	Name string
+	Path string
 }
 
 // Interface represents a Go interface.
+// Kind is KindInterface, KindStruct for an interface extracted from the method set of a struct type,
+// or KindFunc for a function type, whose signature is the one of the only method named Func.
+// Type is the source type as written in the generated code, such as pkg.Repository[T] or pkg.Repository[int].
+// Instantiation is the instantiation given for an instance of a generic interface, such as Repository[int].
 type Interface struct {
-	Name    string
-	Methods []Method
+	Name           string
+	Instantiation  string
+	Kind           Kind
+	ImportPath     string
+	Type           string
+	Doc            string
+	TypeParameters []TypeParameter
+	Embeds         []Embed
+	Methods        []Method
+	Position       token.Position
 }
 
 // Package represents a Go package.
 type Package struct {
 	Name       string
+	Path       string
 	Imports    []Import
 	Interfaces []Interface
 }
@@ -0,0 +1,206 @@
+package template
+
+import (
+	"cmp"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kokhanevych/gomockgen/internal"
+)
+
+// funcs are the functions available in every template.
+var funcs = template.FuncMap{
+	"comment":       comment,
+	"camel":         camel,
+	"snake":         snake,
+	"lowerFirst":    lowerFirst,
+	"upperFirst":    upperFirst,
+	"join":          join,
+	"zeroValue":     zeroValue,
+	"receiverName":  receiverName,
+	"paramNames":    paramNames,
+	"paramList":     paramList,
+	"resultList":    resultList,
+	"fixedParams":   fixedParams,
+	"variadicParam": variadicParam,
+	"hasContext":    hasContext,
+	"returnsError":  returnsError,
+}
+
+// comment returns the text as Go line comments.
+func comment(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight("// "+l, " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// camel returns the identifier in lower camel case: user_id becomes userId and HTTPServer becomes httpServer.
+func camel(s string) string {
+	w := words(s)
+	for i := range w {
+		if i == 0 {
+			w[i] = strings.ToLower(w[i])
+		} else {
+			w[i] = upperFirst(w[i])
+		}
+	}
+
+	return strings.Join(w, "")
+}
+
+// snake returns the identifier in snake case: HTTPServer becomes http_server.
+func snake(s string) string {
+	w := words(s)
+	for i := range w {
+		w[i] = strings.ToLower(w[i])
+	}
+
+	return strings.Join(w, "_")
+}
+
+// lowerFirst returns the string with its first letter in lower case.
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	c, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(c)) + s[n:]
+}
+
+// upperFirst returns the string with its first letter in upper case.
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	c, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(c)) + s[n:]
+}
+
+// words splits the identifier into words at underscores, hyphens, spaces and case changes,
+// keeping initialisms together: HTTPServer is made of HTTP and Server.
+func words(s string) []string {
+	var r []string
+
+	runes := []rune(s)
+	start := -1
+	for i, c := range runes {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			if start >= 0 {
+				r = append(r, string(runes[start:i]))
+				start = -1
+			}
+
+			continue
+		}
+
+		if start >= 0 && unicode.IsUpper(c) {
+			prev := runes[i-1]
+			next := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if !unicode.IsUpper(prev) || next {
+				r = append(r, string(runes[start:i]))
+				start = i
+			}
+		}
+
+		if start < 0 {
+			start = i
+		}
+	}
+
+	if start >= 0 {
+		r = append(r, string(runes[start:]))
+	}
+
+	return r
+}
+
+// join concatenates the elements with the separator.
+// The separator comes first so that join can end a pipeline: {{paramNames .Parameters | join ", "}}.
+func join(sep string, elems []string) string {
+	return strings.Join(elems, sep)
+}
+
+// zeroValue returns an expression of the zero value of the type of the variable.
+func zeroValue(v internal.Variable) string {
+	if v.TypeInfo.Zero != "" {
+		return v.TypeInfo.Zero
+	}
+
+	return "*new(" + v.Type + ")"
+}
+
+// receiverName returns the receiver of the mock of the interface: the <Name>Receiver substitution, m by default.
+// It is the receiver that parameters are renamed not to clash with.
+func receiverName(iface internal.Interface, substitutions map[string]string) string {
+	return cmp.Or(substitutions[iface.Name+"Receiver"], "m")
+}
+
+// paramNames returns the names of the variables.
+func paramNames(vars []internal.Variable) []string {
+	r := make([]string, len(vars))
+	for i, v := range vars {
+		r[i] = v.Name
+	}
+
+	return r
+}
+
+// paramList returns the variables as a parameter list without parentheses: a int, b ...string.
+func paramList(vars []internal.Variable) string {
+	r := make([]string, len(vars))
+	for i, v := range vars {
+		r[i] = strings.TrimSpace(v.Name + " " + v.Type)
+	}
+
+	return strings.Join(r, ", ")
+}
+
+// resultList returns the variables as the result list of a signature: nothing, a single unnamed type,
+// or a list in parentheses, with names if the results are named.
+func resultList(vars []internal.Variable) string {
+	if len(vars) == 0 {
+		return ""
+	}
+
+	if len(vars) == 1 && vars[0].Name == "" {
+		return vars[0].Type
+	}
+
+	return "(" + paramList(vars) + ")"
+}
+
+// fixedParams returns the parameters of the method before the variadic one, all of them if it is not variadic.
+func fixedParams(m internal.Method) []internal.Variable {
+	if !m.Variadic {
+		return m.Parameters
+	}
+
+	return m.Parameters[:len(m.Parameters)-1]
+}
+
+// variadicParam returns the variadic parameter of the method, whose Type is ...T, or a zero variable if it is not variadic.
+func variadicParam(m internal.Method) internal.Variable {
+	if !m.Variadic {
+		return internal.Variable{}
+	}
+
+	return m.Parameters[len(m.Parameters)-1]
+}
+
+// hasContext reports whether the first parameter of the method is a context.Context.
+func hasContext(m internal.Method) bool {
+	return len(m.Parameters) > 0 && m.Parameters[0].Type == "context.Context"
+}
+
+// returnsError reports whether the last result of the method is an error.
+func returnsError(m internal.Method) bool {
+	return len(m.Results) > 0 && m.Results[len(m.Results)-1].Type == "error"
+}
@@ -0,0 +1,114 @@
+package template
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kokhanevych/gomockgen/internal"
+)
+
+func TestFuncs(t *testing.T) {
+	method := internal.Method{
+		Name: "Get",
+		Parameters: []internal.Variable{
+			{Name: "ctx", Type: "context.Context"},
+			{Name: "ids", Type: "...string"},
+		},
+		Results: []internal.Variable{
+			{Type: "*User", TypeInfo: internal.TypeInfo{Zero: "nil"}},
+			{Type: "T"},
+			{Type: "error", TypeInfo: internal.TypeInfo{Zero: "nil"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		data     any
+		want     string
+	}{
+		{
+			name:     "comment",
+			template: `{{comment "a\n\nb"}}`,
+			want:     "// a\n//\n// b",
+		}, {
+			name:     "camel",
+			template: `{{camel "user_id"}} {{camel "HTTPServer"}} {{camel "GetUserByID"}} {{camel "io-reader2"}}`,
+			want:     "userId httpServer getUserByID ioReader2",
+		}, {
+			name:     "snake",
+			template: `{{snake "HTTPServer"}} {{snake "GetUserByID"}} {{snake "userID"}} {{snake "user id"}}`,
+			want:     "http_server get_user_by_id user_id user_id",
+		}, {
+			name:     "lower first",
+			template: `{{lowerFirst "Store"}} {{lowerFirst "ÉtatStore"}} {{lowerFirst ""}}`,
+			want:     "store étatStore ",
+		}, {
+			name:     "upper first",
+			template: `{{upperFirst "id"}} {{upperFirst ""}}`,
+			want:     "Id ",
+		}, {
+			name:     "params",
+			template: `{{paramList .Parameters}}|{{paramNames .Parameters | join ", "}}`,
+			data:     method,
+			want:     "ctx context.Context, ids ...string|ctx, ids",
+		}, {
+			name:     "variadic",
+			template: `{{paramList (fixedParams .)}}|{{(variadicParam .).Name}}`,
+			data:     internal.Method{Parameters: method.Parameters, Variadic: true},
+			want:     "ctx context.Context|ids",
+		}, {
+			name:     "not variadic",
+			template: `{{paramList (fixedParams .)}}|{{(variadicParam .).Name}}`,
+			data:     internal.Method{Parameters: method.Parameters[:1]},
+			want:     "ctx context.Context|",
+		}, {
+			name:     "results",
+			template: `{{resultList .Results}}|{{resultList (slice .Results 2)}}|{{resultList (slice .Results 0 0)}}`,
+			data:     method,
+			want:     "(*User, T, error)|error|",
+		}, {
+			name:     "named results",
+			template: `{{resultList .}}`,
+			data:     []internal.Variable{{Name: "n", Type: "int"}},
+			want:     "(n int)",
+		}, {
+			name:     "zero values",
+			template: `{{range .Results}}{{zeroValue .}} {{end}}`,
+			data:     method,
+			want:     "nil *new(T) nil ",
+		}, {
+			name:     "context and error",
+			template: `{{hasContext .}} {{returnsError .}}`,
+			data:     method,
+			want:     "true true",
+		}, {
+			name:     "no context nor error",
+			template: `{{hasContext .}} {{returnsError .}}`,
+			data:     internal.Method{Parameters: []internal.Variable{{Name: "id", Type: "string"}}},
+			want:     "false false",
+		}, {
+			name:     "receiver name",
+			template: `{{receiverName .Interface .Substitutions}} {{receiverName .Interface nil}}`,
+			data: map[string]any{
+				"Interface":     internal.Interface{Name: "Store"},
+				"Substitutions": map[string]string{"StoreReceiver": "s"},
+			},
+			want: "s m",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := template.New(tt.name).Funcs(funcs).Parse(tt.template)
+			require.NoError(t, err)
+
+			var got strings.Builder
+			require.NoError(t, tmpl.Execute(&got, tt.data))
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
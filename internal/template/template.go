@@ -2,7 +2,12 @@ package template
 
 import (
 	_ "embed"
+	"fmt"
 	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
 	"text/template"
 
 	"github.com/kokhanevych/gomockgen/internal"
@@ -11,6 +16,22 @@ import (
 //go:embed mock.tmpl
 var defaultTemplate string
 
+//go:embed gomock.tmpl
+var gomockTemplate string
+
+//go:embed moq.tmpl
+var moqTemplate string
+
+//go:embed fake.tmpl
+var fakeTemplate string
+
+// builtins are the built-in templates other than the default one by name.
+var builtins = map[string]string{
+	"gomock": gomockTemplate,
+	"moq":    moqTemplate,
+	"fake":   fakeTemplate,
+}
+
 type data struct {
 	Package       internal.Package
 	Substitutions map[string]string
@@ -23,7 +44,7 @@ type Template struct {
 
 // New returns a new template.
 func New(fileName string) (*Template, error) {
-	tmpl, err := template.ParseFiles(fileName)
+	tmpl, err := template.New(filepath.Base(fileName)).Funcs(funcs).ParseFiles(fileName)
 	if err != nil {
 		return nil, err
 	}
@@ -33,7 +54,7 @@ func New(fileName string) (*Template, error) {
 
 // Default returns the default template.
 func Default() (*Template, error) {
-	tmpl, err := template.New("mock").Parse(defaultTemplate)
+	tmpl, err := template.New("mock").Funcs(funcs).Parse(defaultTemplate)
 	if err != nil {
 		return nil, err
 	}
@@ -41,6 +62,42 @@ func Default() (*Template, error) {
 	return &Template{tmpl}, nil
 }
 
+// Builtin returns the built-in template with the name: testify for the default template, gomock, moq or fake.
+func Builtin(name string) (*Template, error) {
+	if name == "testify" {
+		return Default()
+	}
+
+	text, ok := builtins[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown built-in template %q, available are testify and %s", name,
+			strings.Join(slices.Sorted(maps.Keys(builtins)), ", "))
+	}
+
+	tmpl, err := template.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Template{tmpl}, nil
+}
+
+// Reserved returns the names of the locals and packages that the generated method uses, which parameters
+// must not shadow, as listed by the reserved template of the template, if any, executed with the method.
+func (t *Template) Reserved(m internal.Method) ([]string, error) {
+	r := t.Lookup("reserved")
+	if r == nil {
+		return nil, nil
+	}
+
+	var b strings.Builder
+	if err := r.Execute(&b, m); err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(b.String()), nil
+}
+
 // Render writes the generated code in the io.Writer.
 func (t *Template) Render(wr io.Writer, pkg internal.Package, substitutions map[string]string) error {
 	return t.Execute(wr, data{pkg, substitutions})
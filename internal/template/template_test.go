@@ -2,6 +2,7 @@ package template
 
 import (
 	"bytes"
+	"go/format"
 	"testing"
 	"text/template"
 
@@ -72,6 +73,246 @@ func TestDefault(t *testing.T) {
 	}
 }
 
+func TestBuiltin(t *testing.T) {
+	tests := []struct {
+		name           string
+		builtin        string
+		assertion      assert.ValueAssertionFunc
+		errorAssertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:           "testify",
+			builtin:        "testify",
+			assertion:      assert.NotNil,
+			errorAssertion: assert.NoError,
+		}, {
+			name:           "gomock",
+			builtin:        "gomock",
+			assertion:      assert.NotNil,
+			errorAssertion: assert.NoError,
+		}, {
+			name:           "moq",
+			builtin:        "moq",
+			assertion:      assert.NotNil,
+			errorAssertion: assert.NoError,
+		}, {
+			name:           "fake",
+			builtin:        "fake",
+			assertion:      assert.NotNil,
+			errorAssertion: assert.NoError,
+		}, {
+			name:           "unknown",
+			builtin:        "mockery",
+			assertion:      assert.Nil,
+			errorAssertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Builtin(tt.builtin)
+
+			tt.assertion(t, got)
+			tt.errorAssertion(t, err)
+		})
+	}
+}
+
+func TestTemplate_Reserved(t *testing.T) {
+	method := internal.Method{Name: "Get", Results: []internal.Variable{{Type: "int"}, {Type: "error"}}}
+
+	tests := []struct {
+		name      string
+		template  string
+		want      []string
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "testify",
+			template:  defaultTemplate,
+			want:      []string{"args", "mock", "f", "ok"},
+			assertion: assert.NoError,
+		}, {
+			name:      "gomock",
+			template:  gomockTemplate,
+			want:      []string{"gomock", "reflect", "ret", "varargs", "mr", "call", "ret0", "ret1"},
+			assertion: assert.NoError,
+		}, {
+			name:      "moq",
+			template:  moqTemplate,
+			want:      []string{"sync", "callInfo"},
+			assertion: assert.NoError,
+		}, {
+			name:      "fake",
+			template:  fakeTemplate,
+			want:      []string{"sync", "ret", "specificReturn", "stub", "fakeReturns"},
+			assertion: assert.NoError,
+		}, {
+			name:      "custom",
+			template:  `{{define "reserved"}} res  err {{.Name}}{{end}}package {{.Package.Name}}`,
+			want:      []string{"res", "err", "Get"},
+			assertion: assert.NoError,
+		}, {
+			name:      "none",
+			template:  `package {{.Package.Name}}`,
+			assertion: assert.NoError,
+		}, {
+			name:      "error",
+			template:  `{{define "reserved"}}{{.Missing}}{{end}}`,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := &Template{template.Must(template.New(tt.name).Funcs(funcs).Parse(tt.template))}
+
+			got, err := tmpl.Reserved(method)
+
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBuiltin_Render(t *testing.T) {
+	pkg := internal.Package{
+		Name:    "mock",
+		Imports: []internal.Import{{Name: "context", Path: "context"}, {Name: "store", Path: "golang.org/fake/store"}},
+		Interfaces: []internal.Interface{
+			{
+				Name: "Store",
+				Kind: internal.KindInterface,
+				Type: "store.Store",
+				Methods: []internal.Method{
+					{
+						Name:       "Get",
+						Doc:        "Get gets a user.",
+						Parameters: []internal.Variable{{Name: "ctx", Type: "context.Context"}, {Name: "id", Type: "string"}},
+						Results:    []internal.Variable{{Type: "*store.User"}, {Type: "error"}},
+					},
+					{
+						Name:       "Log",
+						Parameters: []internal.Variable{{Name: "format", Type: "string"}, {Name: "args2", Type: "...any"}},
+						Variadic:   true,
+					},
+					{Name: "Close"},
+				},
+			}, {
+				Name:           "Repository",
+				Kind:           internal.KindInterface,
+				Type:           "store.Repository[T, K]",
+				TypeParameters: []internal.TypeParameter{{Name: "T", Constraint: "any"}, {Name: "K", Constraint: "comparable"}},
+				Methods: []internal.Method{
+					{
+						Name:       "Find",
+						Parameters: []internal.Variable{{Name: "id", Type: "K"}},
+						Results:    []internal.Variable{{Name: "v", Type: "T"}, {Name: "ok", Type: "bool"}},
+					},
+				},
+			}, {
+				Name:    "Client",
+				Kind:    internal.KindStruct,
+				Type:    "store.Client",
+				Methods: []internal.Method{{Name: "Do", Parameters: []internal.Variable{{Name: "p0", Type: "...string"}}, Variadic: true}},
+			}, {
+				Name:    "RetryPolicy",
+				Kind:    internal.KindFunc,
+				Type:    "store.RetryPolicy",
+				Methods: []internal.Method{{Name: "Func", Parameters: []internal.Variable{{Name: "n", Type: "int"}}, Results: []internal.Variable{{Type: "bool"}}}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		builtin string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "gomock",
+			builtin: "gomock",
+			want: []string{
+				"func NewMockStore(ctrl *gomock.Controller) *MockStore {",
+				"var _ store.Store = (*MockStore)(nil)",
+				"func (mr *MockStoreMockRecorder) Get(ctx any, id any) *MockStoreGetCall {",
+				"func (c *MockStoreGetCall) Return(arg0 *store.User, arg1 error) *MockStoreGetCall {",
+				"func (c *MockStoreGetCall) DoAndReturn(f func(context.Context, string) (*store.User, error)) *MockStoreGetCall {",
+				"func (mr *MockStoreMockRecorder) Log(format any, args2 ...any) *MockStoreLogCall {",
+				"varargs := append([]any{format}, args2...)",
+				"func NewMockRepository[T any, K comparable](ctrl *gomock.Controller) *MockRepository[T, K] {",
+				"func (m *MockRepository[T, K]) Find(id K) (v T, ok bool) {",
+				"func (mr *MockClientMockRecorder) Do(p0 ...any) *MockClientDoCall {",
+				"type ClientInterface interface {",
+				"func (m *MockRetryPolicy) Func(n int) bool {",
+			},
+			notWant: []string{"var _ store.Client", "var _ store.RetryPolicy"},
+		}, {
+			name:    "moq",
+			builtin: "moq",
+			want: []string{
+				"var _ store.Store = (*StoreMock)(nil)",
+				"GetFunc func(ctx context.Context, id string) (*store.User, error)",
+				"m.lockGet.Lock()",
+				"return m.GetFunc(ctx, id)",
+				"func (m *StoreMock) GetCalls() []struct {\n\tCtx context.Context\n\tId  string\n} {",
+				"Args2  []any",
+				"m.LogFunc(format, args2...)",
+				"func (m *StoreMock) CloseCalls() []struct{} {",
+				"func (m *RepositoryMock[T, K]) Find(id K) (v T, ok bool) {",
+				"func (m *ClientMock) Do(p0 ...string) {",
+				"type ClientInterface interface {",
+				"func (m *RetryPolicyMock) Func(n int) bool {",
+			},
+			notWant: []string{"var _ store.Client", "var _ store.RetryPolicy"},
+		}, {
+			name:    "fake",
+			builtin: "fake",
+			want: []string{
+				"var _ store.Store = (*FakeStore)(nil)",
+				"func (m *FakeStore) GetCalls(stub func(context.Context, string) (*store.User, error)) {",
+				"ret, specificReturn := m.getReturnsOnCall[len(m.getArgsForCall)]",
+				"return stub(ctx, id)",
+				"func (m *FakeStore) GetCallCount() int {",
+				"func (m *FakeStore) GetArgsForCall(i int) (context.Context, string) {",
+				"func (m *FakeStore) GetReturns(result0 *store.User, result1 error) {",
+				"func (m *FakeStore) GetReturnsOnCall(i int, result0 *store.User, result1 error) {",
+				"stub(format, args2...)",
+				"func (m *FakeStore) LogArgsForCall(i int) (string, []any) {",
+				"func (m *FakeStore) Invocations() map[string][][]any {",
+				"func (m *FakeRepository[T, K]) FindReturns(result0 T, result1 bool) {",
+				"func (m *FakeClient) DoArgsForCall(i int) []string {",
+				"type ClientInterface interface {",
+				"func (m *FakeRetryPolicy) FuncReturns(result0 bool) {",
+			},
+			notWant: []string{"var _ store.Client", "var _ store.RetryPolicy"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := Builtin(tt.builtin)
+			require.NoError(t, err)
+
+			var b bytes.Buffer
+			require.NoError(t, tmpl.Render(&b, pkg, map[string]string{"Assert": "true"}))
+
+			got, err := format.Source(b.Bytes())
+			require.NoError(t, err, b.String())
+
+			for _, w := range tt.want {
+				assert.Contains(t, string(got), w)
+			}
+
+			for _, w := range tt.notWant {
+				assert.NotContains(t, string(got), w)
+			}
+
+			b.Reset()
+			require.NoError(t, tmpl.Render(&b, pkg, map[string]string{"Assert": "false"}))
+			assert.NotContains(t, b.String(), "var _ store.")
+		})
+	}
+}
+
 func TestTemplate_Render(t *testing.T) {
 	pkg := internal.Package{
 		Name:    "b",
@@ -88,12 +329,34 @@ func TestTemplate_Render(t *testing.T) {
 					},
 					{
 						Name:       "Print",
+						Doc:        "Print writes the bytes.\n\nIt returns the number of bytes written.",
 						Parameters: []internal.Variable{{Name: "p0", Type: "io2.Writer"}, {Name: "p1", Type: "[]byte"}},
 						Results:    []internal.Variable{{Name: "n", Type: "int"}, {Name: "err", Type: "error"}},
 					},
 				},
 			}, {
 				Name: "I2",
+			}, {
+				Name:           "I3",
+				TypeParameters: []internal.TypeParameter{{Name: "T", Constraint: "any"}, {Name: "K", Constraint: "comparable"}},
+				Methods: []internal.Method{
+					{
+						Name:       "Get",
+						Parameters: []internal.Variable{{Name: "id", Type: "K"}},
+						Results:    []internal.Variable{{Type: "T"}, {Type: "error"}},
+					},
+				},
+			}, {
+				Name: "S",
+				Kind: internal.KindStruct,
+				Methods: []internal.Method{
+					{
+						Name:       "Get",
+						Parameters: []internal.Variable{{Name: "id", Type: "string"}},
+						Results:    []internal.Variable{{Type: "bool"}},
+					},
+					{Name: "Reset"},
+				},
 			},
 		},
 	}
@@ -116,6 +379,10 @@ func (i *I1) F(b B, args ...string) (error) {
 }
 
 // Print is a mocked method on I1.
+//
+// Print writes the bytes.
+//
+// It returns the number of bytes written.
 func (i *I1) Print(p0 io2.Writer, p1 []byte) (int, error) {
 	args := i.Called(p0, p1)
 	return args.Int(0), args.Error(1)
@@ -124,6 +391,35 @@ func (i *I1) Print(p0 io2.Writer, p1 []byte) (int, error) {
 // I2 is an interface mock.
 type I2 struct { mock.Mock }
 
+// I3 is a mock.
+type I3[T any, K comparable] struct { mock.Mock }
+
+// Get is a mocked method on I3.
+func (m *I3[T, K]) Get(id K) (T, error) {
+	args := m.Called(id)
+	return args.Get(0).(T), args.Error(1)
+}
+
+// S is a mock.
+type S struct { mock.Mock }
+
+// Get is a mocked method on S.
+func (m *S) Get(id string) (bool) {
+	args := m.Called(id)
+	return args.Bool(0)
+}
+
+// Reset is a mocked method on S.
+func (m *S) Reset() () {
+	m.Called()
+}
+
+// Getter is an interface implemented by S.
+type Getter interface {
+	Get(id string) (bool)
+	Reset() ()
+}
+
 `
 
 	tmpl, err := Default()
@@ -143,10 +439,227 @@ type I2 struct { mock.Mock }
 		{
 			name:      "nominal",
 			tmpl:      tmpl,
-			args:      args{pkg, map[string]string{"I1Receiver": "i", "I2Comment": "I2 is an interface mock."}},
+			args:      args{pkg, map[string]string{"I1Receiver": "i", "I2Comment": "I2 is an interface mock.", "SInterface": "Getter"}},
 			want:      want,
 			assertion: assert.NoError,
 		},
+		{
+			name: "assert",
+			tmpl: tmpl,
+			args: args{
+				internal.Package{
+					Name:    "mock",
+					Imports: []internal.Import{{Name: "io", Path: "io"}},
+					Interfaces: []internal.Interface{
+						{Name: "Closer", Kind: internal.KindInterface, Type: "io.Closer"},
+						{Name: "S", Kind: internal.KindStruct, Type: "a.S"},
+					},
+				},
+				map[string]string{"Assert": "true"},
+			},
+			want: `package mock
+
+import (	
+	"github.com/stretchr/testify/mock"
+	"io"
+)
+
+// Closer is a mock.
+type Closer struct { mock.Mock }
+
+var _ io.Closer = (*Closer)(nil)
+
+// S is a mock.
+type S struct { mock.Mock }
+
+// SInterface is an interface implemented by S.
+type SInterface interface {
+}
+
+`,
+			assertion: assert.NoError,
+		},
+		{
+			name: "assert disabled",
+			tmpl: tmpl,
+			args: args{
+				internal.Package{
+					Name:       "mock",
+					Imports:    []internal.Import{{Name: "io", Path: "io"}},
+					Interfaces: []internal.Interface{{Name: "Closer", Kind: internal.KindInterface, Type: "io.Closer"}},
+				},
+				map[string]string{"Assert": "false"},
+			},
+			want: `package mock
+
+import (	
+	"github.com/stretchr/testify/mock"
+	"io"
+)
+
+// Closer is a mock.
+type Closer struct { mock.Mock }
+
+`,
+			assertion: assert.NoError,
+		},
+		{
+			name: "expecter disabled",
+			tmpl: tmpl,
+			args: args{
+				internal.Package{
+					Name: "mock",
+					Interfaces: []internal.Interface{{
+						Name:    "Logger",
+						Kind:    internal.KindInterface,
+						Methods: []internal.Method{{Name: "Level", Results: []internal.Variable{{Type: "int"}}}},
+					}},
+				},
+				map[string]string{"Expecter": "false"},
+			},
+			want: `package mock
+
+import (	
+	"github.com/stretchr/testify/mock"
+)
+
+// Logger is a mock.
+type Logger struct { mock.Mock }
+
+// Level is a mocked method on Logger.
+func (m *Logger) Level() (int) {
+	args := m.Called()
+	return args.Int(0)
+}
+
+`,
+			assertion: assert.NoError,
+		},
+		{
+			name: "expecter",
+			tmpl: tmpl,
+			args: args{
+				internal.Package{
+					Name: "mock",
+					Interfaces: []internal.Interface{{
+						Name: "Logger",
+						Kind: internal.KindInterface,
+						Methods: []internal.Method{
+							{
+								Name:       "Log",
+								Parameters: []internal.Variable{{Name: "format", Type: "string"}, {Name: "args2", Type: "...any"}},
+								Results:    []internal.Variable{{Name: "_", Type: "int"}, {Name: "err", Type: "error"}},
+								Variadic:   true,
+							},
+							{Name: "Sync"},
+						},
+					}},
+				},
+				map[string]string{"Expecter": "true"},
+			},
+			want: `package mock
+
+import (	
+	"github.com/stretchr/testify/mock"
+)
+
+// Logger is a mock.
+type Logger struct { mock.Mock }
+
+// Log is a mocked method on Logger.
+func (m *Logger) Log(format string, args2 ...any) (int, error) {
+	args := m.Called(format, args2)
+	if f, ok := args.Get(0).(func(string, ...any) (int, error)); ok {
+		return f(format, args2...)
+	}
+	return args.Int(0), args.Error(1)
+}
+
+// Sync is a mocked method on Logger.
+func (m *Logger) Sync() () {
+	m.Called()
+}
+
+// LoggerExpecter sets typed expectations on the calls to Logger.
+type LoggerExpecter struct {
+	mock *mock.Mock
+}
+
+// EXPECT returns the expecter of the calls to Logger.
+func (m *Logger) EXPECT() *LoggerExpecter {
+	return &LoggerExpecter{mock: &m.Mock}
+}
+
+// LoggerLogCall wraps *mock.Call with the types of Log.
+type LoggerLogCall struct {
+	*mock.Call
+}
+
+// Log expects a call to Log with arguments matching format string, args2 ...any.
+func (m *LoggerExpecter) Log(format any, args2 any) *LoggerLogCall {
+	return &LoggerLogCall{Call: m.mock.On("Log", format, args2)}
+}
+
+// Run sets a handler called with the arguments of Log.
+func (m *LoggerLogCall) Run(run func(format string, args2 ...any)) *LoggerLogCall {
+	m.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []any
+		if args[1] != nil {
+			arg1 = args[1].([]any)
+		}
+		run(arg0, arg1...)
+	})
+	return m
+}
+
+// Return sets the results of Log.
+func (m *LoggerLogCall) Return(r0 int, r1 error) *LoggerLogCall {
+	m.Call.Return(r0, r1)
+	return m
+}
+
+// RunAndReturn sets a function computing the results of Log from its arguments.
+func (m *LoggerLogCall) RunAndReturn(run func(string, ...any) (int, error)) *LoggerLogCall {
+	m.Call.Return(run)
+	return m
+}
+
+// LoggerSyncCall wraps *mock.Call with the types of Sync.
+type LoggerSyncCall struct {
+	*mock.Call
+}
+
+// Sync expects a call to Sync.
+func (m *LoggerExpecter) Sync() *LoggerSyncCall {
+	return &LoggerSyncCall{Call: m.mock.On("Sync")}
+}
+
+// Run sets a handler called with the arguments of Sync.
+func (m *LoggerSyncCall) Run(run func()) *LoggerSyncCall {
+	m.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return m
+}
+
+// Return sets the results of Sync.
+func (m *LoggerSyncCall) Return() *LoggerSyncCall {
+	m.Call.Return()
+	return m
+}
+
+// RunAndReturn sets a function computing the results of Sync from its arguments.
+func (m *LoggerSyncCall) RunAndReturn(run func() ()) *LoggerSyncCall {
+	return m.Run(run)
+}
+
+`,
+			assertion: assert.NoError,
+		},
 		{
 			name:      "error",
 			tmpl:      &Template{&template.Template{}},